@@ -0,0 +1,161 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const albumConcurrency = 3
+
+type AlbumItem struct {
+	Type     MediaType
+	Content  interface{}
+	Caption  string
+	Mimetype string
+}
+
+type AlbumResult struct {
+	Album  *Message
+	Sent   []*Message
+	Failed map[int]error
+}
+
+type AlbumError struct {
+	Total  int
+	Failed map[int]error
+}
+
+func (e *AlbumError) Error() string {
+	indexes := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	parts := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		parts = append(parts, fmt.Sprintf("item %d: %v", i, e.Failed[i]))
+	}
+	return fmt.Sprintf("album: %d of %d items failed (%s)", len(e.Failed), e.Total, strings.Join(parts, "; "))
+}
+
+func (m *Message) SendAlbum(items []AlbumItem, options ...SendOptions) (*AlbumResult, error) {
+	if len(items) < 2 {
+		return nil, fmt.Errorf("an album needs at least two items, got %d", len(items))
+	}
+
+	var opts SendOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	result := &AlbumResult{Failed: make(map[int]error)}
+	built := make([]*waE2E.Message, len(items))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, albumConcurrency)
+
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item AlbumItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			msg, err := m.buildAlbumItem(item, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed[i] = err
+				return
+			}
+			built[i] = msg
+		}(i, item)
+	}
+	wg.Wait()
+
+	var images, videos uint32
+	for _, msg := range built {
+		if msg == nil {
+			continue
+		}
+		if msg.ImageMessage != nil {
+			images++
+		} else {
+			videos++
+		}
+	}
+
+	if images+videos == 0 {
+		return result, &AlbumError{Total: len(items), Failed: result.Failed}
+	}
+
+	albumOpts := opts
+	albumOpts.ViewOnce = false
+	album, err := m.deliver(&waE2E.Message{
+		AlbumMessage: &waE2E.AlbumMessage{
+			ExpectedImageCount: proto.Uint32(images),
+			ExpectedVideoCount: proto.Uint32(videos),
+			ContextInfo:        m.contextInfo("", albumOpts),
+		},
+	}, albumOpts)
+	if err != nil {
+		return nil, err
+	}
+	result.Album = album
+
+	parentKey := m.Client.BuildMessageKey(m.Chat, *m.Client.Store.ID, album.ID)
+	for i, msg := range built {
+		if msg == nil {
+			continue
+		}
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{
+			MessageAssociation: &waE2E.MessageAssociation{
+				AssociationType:  waE2E.MessageAssociation_MEDIA_ALBUM.Enum(),
+				ParentMessageKey: parentKey,
+			},
+		}
+		sent, err := m.deliver(msg, SendOptions{})
+		if err != nil {
+			result.Failed[i] = err
+			continue
+		}
+		result.Sent = append(result.Sent, sent)
+	}
+
+	if len(result.Failed) > 0 {
+		return result, &AlbumError{Total: len(items), Failed: result.Failed}
+	}
+	return result, nil
+}
+
+func (m *Message) buildAlbumItem(item AlbumItem, opts SendOptions) (*waE2E.Message, error) {
+	data, _, err := loadMedia(item.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	itemOpts := opts
+	itemOpts.Caption = item.Caption
+	itemOpts.ViewOnce = false
+	itemOpts.Quoted = nil
+	itemOpts.Mimetype = item.Mimetype
+	if itemOpts.Mimetype == "" {
+		itemOpts.Mimetype = detectMimetype(item.Type, data)
+	}
+
+	switch item.Type {
+	case MediaImage:
+		return m.buildImage(data, itemOpts)
+	case MediaVideo:
+		return m.buildVideo(data, itemOpts)
+	default:
+		return nil, fmt.Errorf("unsupported album media type: %s", item.Type)
+	}
+}
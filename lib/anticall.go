@@ -0,0 +1,151 @@
+package lib
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	CallOff    = "off"
+	CallReject = "reject"
+	CallBlock  = "block"
+)
+
+type CallPolicy struct {
+	Mode    string
+	Message string
+	Limit   int
+	Window  int
+}
+
+var callMutex sync.Mutex
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS call_attempts (
+			caller TEXT PRIMARY KEY,
+			attempts INTEGER NOT NULL,
+			first_call INTEGER NOT NULL
+		)`,
+	)
+}
+
+func GetCallPolicy() (*CallPolicy, error) {
+	p := &CallPolicy{}
+	var err error
+	if p.Mode, err = GetSetting("anticall.mode", Config.ANTI_CALL); err != nil {
+		return nil, err
+	}
+	if p.Message, err = GetSetting("anticall.message", Config.ANTI_CALL_MSG); err != nil {
+		return nil, err
+	}
+	limit, err := GetSetting("anticall.limit", strconv.Itoa(Config.ANTI_CALL_LIMIT))
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := GetSetting("anticall.window", strconv.Itoa(Config.ANTI_CALL_WINDOW))
+	if err != nil {
+		return nil, err
+	}
+
+	p.Limit, _ = strconv.Atoi(limit)
+	if p.Limit < 1 {
+		p.Limit = 3
+	}
+	p.Window, _ = strconv.Atoi(window)
+	if p.Window < 1 {
+		p.Window = 60
+	}
+	if p.Mode != CallReject && p.Mode != CallBlock {
+		p.Mode = CallOff
+	}
+	return p, nil
+}
+
+func SaveCallPolicy(p *CallPolicy) error {
+	if err := SetSetting("anticall.mode", p.Mode); err != nil {
+		return err
+	}
+	if err := SetSetting("anticall.message", p.Message); err != nil {
+		return err
+	}
+	if err := SetSetting("anticall.limit", strconv.Itoa(p.Limit)); err != nil {
+		return err
+	}
+	return SetSetting("anticall.window", strconv.Itoa(p.Window))
+}
+
+// recordCall counts a call from caller within the policy window. Counts are
+// kept in the database so they survive restarts and expire with the window.
+func recordCall(caller types.JID, window time.Duration) (int, error) {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+
+	now := time.Now()
+	if _, err := DB.Exec(`DELETE FROM call_attempts WHERE first_call < ?`, now.Add(-window).Unix()); err != nil {
+		return 0, err
+	}
+
+	attempts, first := 0, now.Unix()
+	err := DB.QueryRow(`SELECT attempts, first_call FROM call_attempts WHERE caller = ?`, caller.String()).Scan(&attempts, &first)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	attempts++
+	_, err = DB.Exec(`INSERT OR REPLACE INTO call_attempts (caller, attempts, first_call) VALUES (?, ?, ?)`,
+		caller.String(), attempts, first)
+	return attempts, err
+}
+
+func resetCalls(caller types.JID) error {
+	_, err := DB.Exec(`DELETE FROM call_attempts WHERE caller = ?`, caller.String())
+	return err
+}
+
+func HandleCall(client *whatsmeow.Client, evt *events.CallOffer) error {
+	policy, err := GetCallPolicy()
+	if err != nil || policy.Mode == CallOff {
+		return err
+	}
+
+	caller := evt.CallCreator.ToNonAD()
+	if caller.Server != types.DefaultUserServer && evt.CallCreatorAlt.Server == types.DefaultUserServer {
+		caller = evt.CallCreatorAlt.ToNonAD()
+	}
+	caller = ToPN(client, caller)
+	if IsSudo(client, caller) {
+		return nil
+	}
+
+	if err := client.RejectCall(context.Background(), evt.From, evt.CallID); err != nil {
+		return err
+	}
+
+	attempts, err := recordCall(caller, time.Duration(policy.Window)*time.Minute)
+	if err != nil {
+		return err
+	}
+	block := policy.Mode == CallBlock && attempts >= policy.Limit
+
+	if policy.Message != "" {
+		if _, err := (&Message{Client: client, Chat: caller}).Send(MediaText, policy.Message); err != nil {
+			return err
+		}
+	}
+	if block {
+		if _, err := client.UpdateBlocklist(context.Background(), caller, events.BlocklistChangeActionBlock); err != nil {
+			return err
+		}
+		return resetCalls(caller)
+	}
+	return nil
+}
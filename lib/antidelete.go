@@ -0,0 +1,115 @@
+package lib
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	AntiDeleteOff   = "off"
+	AntiDeleteChat  = "chat"
+	AntiDeleteOwner = "owner"
+)
+
+// AntiDeleteSettings holds the per-chat anti-delete configuration. Admins
+// also recovers messages removed by group admins, and Hours limits how old a
+// deleted message may be, capped by MSG_STORE_HOURS.
+type AntiDeleteSettings struct {
+	Chat   types.JID
+	Mode   string
+	Admins bool
+	Hours  int
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS antidelete (
+			chat TEXT PRIMARY KEY,
+			mode TEXT NOT NULL,
+			admins INTEGER NOT NULL DEFAULT 0,
+			hours INTEGER NOT NULL DEFAULT 0
+		)`,
+	)
+}
+
+func GetAntiDelete(chat types.JID) (*AntiDeleteSettings, error) {
+	s := &AntiDeleteSettings{Chat: chat}
+	err := DB.QueryRow(`SELECT mode, admins, hours FROM antidelete WHERE chat = ?`, chat.String()).Scan(&s.Mode, &s.Admins, &s.Hours)
+	if err == sql.ErrNoRows {
+		s.Mode = Config.ANTI_DELETE
+	} else if err != nil {
+		return nil, err
+	}
+	if s.Mode != AntiDeleteChat && s.Mode != AntiDeleteOwner {
+		s.Mode = AntiDeleteOff
+	}
+	if s.Hours <= 0 || s.Hours > Config.MSG_STORE_HOURS {
+		s.Hours = Config.MSG_STORE_HOURS
+	}
+	return s, nil
+}
+
+func SaveAntiDelete(s *AntiDeleteSettings) error {
+	_, err := DB.Exec(`INSERT OR REPLACE INTO antidelete (chat, mode, admins, hours) VALUES (?, ?, ?, ?)`,
+		s.Chat.String(), s.Mode, s.Admins, s.Hours)
+	return err
+}
+
+func HandleRevoke(m *Message) error {
+	if !m.IsRevoke || m.FromMe || DB == nil {
+		return nil
+	}
+
+	settings, err := GetAntiDelete(m.Chat)
+	if err != nil || settings.Mode == AntiDeleteOff {
+		return err
+	}
+
+	original, err := LoadMessage(m.Chat, m.RevokedID)
+	if err != nil || original == nil || original.Info.IsFromMe {
+		return err
+	}
+	if !settings.Admins && !SameUser(m.Client, original.Info.Sender, m.Sender) {
+		return nil
+	}
+	if time.Since(original.Info.Timestamp) > time.Duration(settings.Hours)*time.Hour {
+		return nil
+	}
+
+	target := m.Chat
+	if settings.Mode == AntiDeleteOwner {
+		if target = OwnerJID(m.Client); target.IsEmpty() {
+			return nil
+		}
+	}
+
+	sender := ToPN(m.Client, original.Info.Sender)
+	header := recoveredHeader(m.Client, "Deleted message", m.Chat, sender, original.Info.Timestamp)
+	mentions := []types.JID{sender}
+	if !SameUser(m.Client, original.Info.Sender, m.Sender) {
+		deleter := ToPN(m.Client, m.Sender)
+		header += fmt.Sprintf("\n*Deleted by:* @%s", deleter.User)
+		mentions = append(mentions, deleter)
+	}
+	if _, err := (&Message{Client: m.Client, Chat: target}).Send(MediaText, header, SendOptions{Mentions: mentions}); err != nil {
+		return err
+	}
+	_, err = (&Message{Client: m.Client, Chat: target}).Resend(original.Message, SendOptions{})
+	return err
+}
+
+func recoveredHeader(client *whatsmeow.Client, title string, chat, sender types.JID, sent time.Time) string {
+	where := "Private chat"
+	if chat.Server == types.GroupServer {
+		where = chat.String()
+		if info, err := GetGroupInfo(client, chat); err == nil {
+			where = info.Name
+		}
+	}
+	return fmt.Sprintf("*%s*\n*From:* @%s\n*Chat:* %s\n*Sent:* %s",
+		title, sender.User, where, sent.Format("2006-01-02 15:04:05"))
+}
@@ -0,0 +1,132 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	waveformSamples = 64
+	waveformRate    = 8000
+)
+
+func HasFFmpeg() bool {
+	_, err := exec.LookPath("ffmpeg")
+	return err == nil
+}
+
+func isOpus(data []byte) bool {
+	if !bytes.HasPrefix(data, []byte("OggS")) {
+		return false
+	}
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return bytes.Contains(head, []byte("OpusHead"))
+}
+
+func ToOpus(ctx context.Context, data []byte) ([]byte, error) {
+	inFile, err := os.CreateTemp("", "wa_audio_in_*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(inFile.Name())
+
+	if _, err := inFile.Write(data); err != nil {
+		inFile.Close()
+		return nil, err
+	}
+	inFile.Close()
+
+	outFile := inFile.Name() + ".ogg"
+	defer os.Remove(outFile)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-i", inFile.Name(), "-vn", "-c:a", "libopus", "-b:a", "48k", "-ac", "1", "-ar", "48000", "-application", "voip", "-f", "ogg", outFile)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %v: %s", err, strings.TrimSpace(lastLine(stderr.String())))
+	}
+
+	return os.ReadFile(outFile)
+}
+
+func AudioWaveform(ctx context.Context, data []byte) (uint32, []byte, error) {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-i", "pipe:0", "-vn", "-ac", "1", "-ar", fmt.Sprint(waveformRate), "-f", "s16le", "pipe:1")
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return 0, nil, err
+	}
+
+	pcm := stdout.Bytes()
+	count := len(pcm) / 2
+	if count == 0 {
+		return 0, nil, fmt.Errorf("no audio samples decoded")
+	}
+
+	samples := make([]float64, count)
+	for i := 0; i < count; i++ {
+		samples[i] = math.Abs(float64(int16(binary.LittleEndian.Uint16(pcm[i*2:]))))
+	}
+
+	seconds := uint32(math.Round(float64(count) / waveformRate))
+	if seconds == 0 {
+		seconds = 1
+	}
+
+	return seconds, buildWaveform(samples), nil
+}
+
+func buildWaveform(samples []float64) []byte {
+	bucket := len(samples) / waveformSamples
+	if bucket == 0 {
+		bucket = 1
+	}
+
+	levels := make([]float64, waveformSamples)
+	peak := 0.0
+	for i := range levels {
+		start := i * bucket
+		if start >= len(samples) {
+			break
+		}
+		end := start + bucket
+		if end > len(samples) {
+			end = len(samples)
+		}
+		sum := 0.0
+		for _, s := range samples[start:end] {
+			sum += s
+		}
+		levels[i] = sum / float64(end-start)
+		if levels[i] > peak {
+			peak = levels[i]
+		}
+	}
+
+	waveform := make([]byte, waveformSamples)
+	if peak == 0 {
+		return waveform
+	}
+	for i, level := range levels {
+		waveform[i] = byte(math.Round(level / peak * 100))
+	}
+	return waveform
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "\n"); i != -1 {
+		return s[i+1:]
+	}
+	return s
+}
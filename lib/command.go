@@ -17,11 +17,12 @@ type Command struct {
 	Desc               string
 	Type               string
 	DontAddCommandList bool
+	React              bool
 	Function           CommandFunc
 }
 
 var Commands []*Command
-var validTypes = []string{"photo", "image", "text", "message", "video", "number", "viewonce", "sticker", "audio", "messages.upsert"}
+var validTypes = []string{"photo", "image", "text", "message", "video", "number", "viewonce", "sticker", "audio", "location", "contact", "poll", "reaction", "edit", "delete", "messages.upsert"}
 var PREFIX string
 var RAGEX string
 
@@ -85,6 +86,9 @@ func Function(info map[string]interface{}, function CommandFunc) *Command {
 	if v, ok := info["dontAddCommandList"].(bool); ok {
 		cmd.DontAddCommandList = v
 	}
+	if v, ok := info["react"].(bool); ok {
+		cmd.React = v
+	}
 
 	_, hasOn := info["on"]
 	_, hasPattern := info["pattern"]
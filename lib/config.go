@@ -15,6 +15,23 @@ type Configuration struct {
 	READ_MSG  bool
 	READ_CMD  bool
 	ERROR_MSG bool
+	DATABASE  string
+	CMD_REACT bool
+	EDIT_CMD  bool
+
+	MSG_STORE_HOURS  int
+	MSG_STORE_MAX    int
+	ANTI_DELETE      string
+	ANTI_VIEWONCE    bool
+	ANTI_CALL        string
+	ANTI_CALL_MSG    string
+	ANTI_CALL_LIMIT  int
+	ANTI_CALL_WINDOW int
+	BAD_WORDS        string
+
+	WARN_LIMIT        int
+	WARN_ACTION       string
+	WARN_MUTE_MINUTES int
 }
 
 var Config Configuration
@@ -32,5 +49,22 @@ func LoadConfig() {
 		READ_MSG:  getEnvBool("READ_MSG", true),
 		READ_CMD:  getEnvBool("READ_CMD", true),
 		ERROR_MSG: getEnvBool("ERROR_MSG", true),
+		DATABASE:  getEnv("DATABASE", "bot.db"),
+		CMD_REACT: getEnvBool("CMD_REACT", false),
+		EDIT_CMD:  getEnvBool("EDIT_CMD", false),
+
+		MSG_STORE_HOURS:  getEnvInt("MSG_STORE_HOURS", 24),
+		MSG_STORE_MAX:    getEnvInt("MSG_STORE_MAX", 1000),
+		ANTI_DELETE:      getEnv("ANTI_DELETE", "off"),
+		ANTI_VIEWONCE:    getEnvBool("ANTI_VIEWONCE", false),
+		ANTI_CALL:        getEnv("ANTI_CALL", "off"),
+		ANTI_CALL_MSG:    getEnv("ANTI_CALL_MSG", ""),
+		ANTI_CALL_LIMIT:  getEnvInt("ANTI_CALL_LIMIT", 3),
+		ANTI_CALL_WINDOW: getEnvInt("ANTI_CALL_WINDOW", 60),
+		BAD_WORDS:        getEnv("BAD_WORDS", ""),
+
+		WARN_LIMIT:        getEnvInt("WARN_LIMIT", 3),
+		WARN_ACTION:       getEnv("WARN_ACTION", "kick"),
+		WARN_MUTE_MINUTES: getEnvInt("WARN_MUTE_MINUTES", 60),
 	}
-}
\ No newline at end of file
+}
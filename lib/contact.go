@@ -0,0 +1,176 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type Contact struct {
+	Name         string
+	Number       string
+	Organization string
+	Email        string
+	VCard        string
+}
+
+func (c Contact) BuildVCard() string {
+	if c.VCard != "" {
+		return c.VCard
+	}
+
+	number := strings.TrimPrefix(c.Number, "+")
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	b.WriteString(fmt.Sprintf("FN:%s\n", escapeVCard(c.Name)))
+	if c.Organization != "" {
+		b.WriteString(fmt.Sprintf("ORG:%s;\n", escapeVCard(c.Organization)))
+	}
+	if number != "" {
+		b.WriteString(fmt.Sprintf("TEL;type=CELL;type=VOICE;waid=%s:+%s\n", number, number))
+	}
+	if c.Email != "" {
+		b.WriteString(fmt.Sprintf("EMAIL:%s\n", escapeVCard(c.Email)))
+	}
+	b.WriteString("END:VCARD")
+	return b.String()
+}
+
+func ParseVCard(vcard string) Contact {
+	contact := Contact{VCard: vcard}
+	unfolded := strings.ReplaceAll(vcard, "\r\n", "\n")
+	unfolded = strings.NewReplacer("\n ", "", "\n\t", "").Replace(unfolded)
+	for _, line := range strings.Split(unfolded, "\n") {
+		idx := strings.Index(line, ":")
+		if idx == -1 {
+			continue
+		}
+		key := strings.ToUpper(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+
+		switch {
+		case key == "FN" || strings.HasPrefix(key, "FN;"):
+			contact.Name = unescapeVCard(value)
+		case strings.HasPrefix(key, "ORG"):
+			contact.Organization = unescapeVCard(firstComponent(value))
+		case strings.HasPrefix(key, "EMAIL"):
+			contact.Email = unescapeVCard(value)
+		case strings.HasPrefix(key, "TEL") && contact.Number == "":
+			if waid := strings.Index(strings.ToLower(line), "waid="); waid != -1 {
+				rest := line[waid+5:]
+				if end := strings.IndexAny(rest, ";:"); end != -1 {
+					rest = rest[:end]
+				}
+				contact.Number = rest
+			} else {
+				contact.Number = strings.Map(func(r rune) rune {
+					if r >= '0' && r <= '9' {
+						return r
+					}
+					return -1
+				}, value)
+			}
+		}
+	}
+	return contact
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeVCard escapes a text value as described in RFC 6350 section 3.4.
+func escapeVCard(value string) string {
+	return vcardEscaper.Replace(value)
+}
+
+func unescapeVCard(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// firstComponent returns the part of a structured value before the first
+// unescaped semicolon.
+func firstComponent(value string) string {
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ';':
+			return value[:i]
+		}
+	}
+	return value
+}
+
+func (m *Message) sendContacts(contacts []Contact, opts SendOptions) (*Message, error) {
+	if len(contacts) == 0 {
+		return nil, fmt.Errorf("no contacts provided")
+	}
+
+	if len(contacts) == 1 {
+		msg := &waE2E.Message{
+			ContactMessage: &waE2E.ContactMessage{
+				DisplayName: proto.String(contacts[0].Name),
+				Vcard:       proto.String(contacts[0].BuildVCard()),
+				ContextInfo: m.contextInfo("", opts),
+			},
+		}
+		return m.deliver(msg, opts)
+	}
+
+	list := make([]*waE2E.ContactMessage, 0, len(contacts))
+	for _, c := range contacts {
+		list = append(list, &waE2E.ContactMessage{
+			DisplayName: proto.String(c.Name),
+			Vcard:       proto.String(c.BuildVCard()),
+		})
+	}
+
+	msg := &waE2E.Message{
+		ContactsArrayMessage: &waE2E.ContactsArrayMessage{
+			DisplayName: proto.String(fmt.Sprintf("%d contacts", len(contacts))),
+			Contacts:    list,
+			ContextInfo: m.contextInfo("", opts),
+		},
+	}
+	return m.deliver(msg, opts)
+}
+
+func parseContacts(msg *waE2E.Message) []Contact {
+	if msg == nil {
+		return nil
+	}
+	if msg.ContactMessage != nil {
+		contact := ParseVCard(msg.ContactMessage.GetVcard())
+		if name := msg.ContactMessage.GetDisplayName(); name != "" {
+			contact.Name = name
+		}
+		return []Contact{contact}
+	}
+	if msg.ContactsArrayMessage != nil {
+		var contacts []Contact
+		for _, c := range msg.ContactsArrayMessage.Contacts {
+			contact := ParseVCard(c.GetVcard())
+			if name := c.GetDisplayName(); name != "" {
+				contact.Name = name
+			}
+			contacts = append(contacts, contact)
+		}
+		return contacts
+	}
+	return nil
+}
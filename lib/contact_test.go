@@ -0,0 +1,76 @@
+package lib
+
+import "testing"
+
+func TestParseVCard(t *testing.T) {
+	tests := []struct {
+		name  string
+		vcard string
+		want  Contact
+	}{
+		{
+			name:  "whatsapp card",
+			vcard: "BEGIN:VCARD\nVERSION:3.0\nFN:John Doe\nORG:Acme;\nTEL;type=CELL;type=VOICE;waid=911234567890:+91 12345 67890\nEMAIL:john@example.com\nEND:VCARD",
+			want:  Contact{Name: "John Doe", Number: "911234567890", Organization: "Acme", Email: "john@example.com"},
+		},
+		{
+			name:  "crlf without waid",
+			vcard: "BEGIN:VCARD\r\nFN:Jane\r\nTEL;TYPE=HOME:+1 (555) 010-9999\r\nEND:VCARD",
+			want:  Contact{Name: "Jane", Number: "15550109999"},
+		},
+		{
+			name:  "folded lines and parameters",
+			vcard: "BEGIN:VCARD\nFN;CHARSET=UTF-8:Very Long\n  Name\nTEL;waid=4412\n 3456789:+44 123 456 789\nEND:VCARD",
+			want:  Contact{Name: "Very Long Name", Number: "44123456789"},
+		},
+		{
+			name:  "escaped values",
+			vcard: "BEGIN:VCARD\nFN:Doe\\, John\nORG:Acme\\; Labs;Research\nEMAIL:a\\\\b@example.com\nEND:VCARD",
+			want:  Contact{Name: "Doe, John", Organization: "Acme; Labs", Email: "a\\b@example.com"},
+		},
+		{
+			name:  "first number wins",
+			vcard: "BEGIN:VCARD\nFN:Multi\nTEL;waid=111111:+111111\nTEL;waid=222222:+222222\nEND:VCARD",
+			want:  Contact{Name: "Multi", Number: "111111"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseVCard(tt.vcard)
+			got.VCard = ""
+			if got != tt.want {
+				t.Errorf("ParseVCard() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildVCardRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Contact
+		want Contact
+	}{
+		{
+			name: "plain",
+			in:   Contact{Name: "Round Trip", Number: "+919876543210", Organization: "Org", Email: "rt@example.com"},
+			want: Contact{Name: "Round Trip", Number: "919876543210", Organization: "Org", Email: "rt@example.com"},
+		},
+		{
+			name: "special characters",
+			in:   Contact{Name: "Doe, John; Jr.\\", Number: "15550100", Organization: "Acme; Labs, Inc\nEurope", Email: "a,b;c@example.com"},
+			want: Contact{Name: "Doe, John; Jr.\\", Number: "15550100", Organization: "Acme; Labs, Inc\nEurope", Email: "a,b;c@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseVCard(tt.in.BuildVCard())
+			got.VCard = ""
+			if got != tt.want {
+				t.Errorf("round trip = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
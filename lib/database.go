@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+var DB *sql.DB
+
+var (
+	schemas     []string
+	schemaMutex sync.Mutex
+)
+
+func registerSchema(stmts ...string) {
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+	schemas = append(schemas, stmts...)
+}
+
+func InitDatabase() error {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", Config.DATABASE))
+	if err != nil {
+		return err
+	}
+	db.SetMaxOpenConns(1)
+
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+	for _, stmt := range schemas {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return fmt.Errorf("failed to apply schema: %w", err)
+		}
+	}
+
+	DB = db
+	return nil
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
+	)
+}
+
+func GetSetting(key, fallback string) (string, error) {
+	var value string
+	err := DB.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return fallback, nil
+	} else if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func SetSetting(key, value string) error {
+	_, err := DB.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)`, key, value)
+	return err
+}
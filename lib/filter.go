@@ -0,0 +1,192 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	MatchExact    = "exact"
+	MatchContains = "contains"
+	MatchRegex    = "regex"
+
+	GlobalChat = "global"
+)
+
+type Filter struct {
+	Chat      string
+	Trigger   string
+	Match     string
+	Response  string
+	Message   *waE2E.Message
+	CreatedAt time.Time
+
+	regex *regexp.Regexp
+}
+
+var (
+	filterCache = make(map[string][]*Filter)
+	filterMutex sync.RWMutex
+)
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS filters (
+			chat TEXT NOT NULL,
+			trigger TEXT NOT NULL,
+			match_type TEXT NOT NULL DEFAULT 'exact',
+			response TEXT NOT NULL DEFAULT '',
+			message BLOB,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (chat, trigger)
+		)`,
+	)
+}
+
+func FilterScope(chat types.JID, global bool) string {
+	if global {
+		return GlobalChat
+	}
+	return chat.String()
+}
+
+func SaveFilter(f *Filter) error {
+	if f.Match == MatchRegex {
+		if _, err := regexp.Compile("(?i)" + f.Trigger); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+
+	var raw []byte
+	if f.Message != nil {
+		var err error
+		if raw, err = proto.Marshal(f.Message); err != nil {
+			return err
+		}
+	}
+
+	trigger := f.Trigger
+	if f.Match != MatchRegex {
+		trigger = strings.ToLower(trigger)
+	}
+
+	_, err := DB.Exec(`INSERT OR REPLACE INTO filters (chat, trigger, match_type, response, message, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		f.Chat, trigger, f.Match, f.Response, raw, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	invalidateFilters(f.Chat)
+	return nil
+}
+
+func DeleteFilter(chat, trigger string) (bool, error) {
+	res, err := DB.Exec(`DELETE FROM filters WHERE chat = ? AND (trigger = ? OR trigger = ?)`, chat, trigger, strings.ToLower(trigger))
+	if err != nil {
+		return false, err
+	}
+	invalidateFilters(chat)
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func GetFilters(chat string) ([]*Filter, error) {
+	filterMutex.RLock()
+	filters, ok := filterCache[chat]
+	filterMutex.RUnlock()
+	if ok {
+		return filters, nil
+	}
+
+	rows, err := DB.Query(`SELECT trigger, match_type, response, message, created_at FROM filters WHERE chat = ? ORDER BY created_at`, chat)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		f := &Filter{Chat: chat}
+		var raw []byte
+		var created int64
+		if err := rows.Scan(&f.Trigger, &f.Match, &f.Response, &raw, &created); err != nil {
+			return nil, err
+		}
+		f.CreatedAt = time.Unix(created, 0)
+		if len(raw) > 0 {
+			f.Message = &waE2E.Message{}
+			if err := proto.Unmarshal(raw, f.Message); err != nil {
+				continue
+			}
+		}
+		if f.Match == MatchRegex {
+			if f.regex, err = regexp.Compile("(?i)" + f.Trigger); err != nil {
+				continue
+			}
+		}
+		filters = append(filters, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	filterMutex.Lock()
+	filterCache[chat] = filters
+	filterMutex.Unlock()
+	return filters, nil
+}
+
+func invalidateFilters(chat string) {
+	filterMutex.Lock()
+	delete(filterCache, chat)
+	filterMutex.Unlock()
+}
+
+func (f *Filter) Matches(text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return false
+	}
+	switch f.Match {
+	case MatchContains:
+		return strings.Contains(text, f.Trigger)
+	case MatchRegex:
+		return f.regex != nil && f.regex.MatchString(text)
+	default:
+		return text == f.Trigger
+	}
+}
+
+func MatchFilters(m *Message) []*Filter {
+	if DB == nil || m.FromMe || m.Text == "" {
+		return nil
+	}
+
+	var matched []*Filter
+	seen := make(map[string]bool)
+	for _, chat := range []string{m.Chat.String(), GlobalChat} {
+		filters, err := GetFilters(chat)
+		if err != nil {
+			continue
+		}
+		for _, f := range filters {
+			if !seen[f.Trigger] && f.Matches(m.Text) {
+				seen[f.Trigger] = true
+				matched = append(matched, f)
+			}
+		}
+	}
+	return matched
+}
+
+func (f *Filter) Respond(m *Message) (*Message, error) {
+	if f.Message != nil {
+		return m.Resend(f.Message, SendOptions{Quoted: m})
+	}
+	return m.Send(MediaText, f.Response, SendOptions{Quoted: m})
+}
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func (m *Message) Forward(target types.JID) (*Message, error) {
+	if m.Data == nil || m.Data.Message == nil {
+		full, err := LoadMessage(m.Chat, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		if full == nil {
+			return nil, fmt.Errorf("message %s not found", m.ID)
+		}
+		return forwardMessage(m.Client, full.Message, target)
+	}
+	return forwardMessage(m.Client, m.Data.Message, target)
+}
+
+func (r *ReplyMessage) Forward(target types.JID) (*Message, error) {
+	return forwardMessage(r.Client, r.Key().Message, target)
+}
+
+func forwardMessage(client *whatsmeow.Client, original *waE2E.Message, target types.JID) (*Message, error) {
+	msg := cloneMessage(original)
+	if msg == nil {
+		return nil, fmt.Errorf("nothing to forward")
+	}
+
+	score := uint32(0)
+	if ctx := getContextInfo(msg); ctx != nil {
+		score = ctx.GetForwardingScore()
+	}
+
+	ctx := &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(score + 1),
+	}
+	if !setContextInfo(msg, ctx) {
+		return nil, fmt.Errorf("unsupported message type for forwarding: %s", getContentType(msg))
+	}
+
+	target = target.ToNonAD()
+	return (&Message{Client: client, Chat: target}).deliver(msg, SendOptions{ViewOnce: isViewOnce(original)})
+}
+
+func (m *Message) Resend(original *waE2E.Message, opts SendOptions) (*Message, error) {
+	msg := cloneMessage(original)
+	if msg == nil {
+		return nil, fmt.Errorf("nothing to send")
+	}
+
+	if ctx := getContextInfo(msg); ctx != nil {
+		for _, jid := range ctx.MentionedJID {
+			if parsed := ParseJID(jid); !parsed.IsEmpty() {
+				opts.Mentions = append(opts.Mentions, parsed)
+			}
+		}
+	}
+
+	setContextInfo(msg, m.contextInfo(getMessageText(msg), opts))
+	return m.deliver(msg, opts)
+}
+
+func cloneMessage(original *waE2E.Message) *waE2E.Message {
+	inner := UnwrapMessage(original)
+	if inner == nil {
+		return nil
+	}
+
+	msg := proto.Clone(inner).(*waE2E.Message)
+	msg.MessageContextInfo = nil
+	if msg.Conversation != nil {
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{Text: msg.Conversation}
+		msg.Conversation = nil
+	}
+	return msg
+}
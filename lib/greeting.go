@@ -0,0 +1,138 @@
+package lib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	GreetingWelcome = "welcome"
+	GreetingGoodbye = "goodbye"
+)
+
+var defaultGreetings = map[string]string{
+	GreetingWelcome: "_Welcome @user to *{group}*!_\n_You are member #{count}_",
+	GreetingGoodbye: "_Goodbye @user, {group} now has {count} members_",
+}
+
+type Greeting struct {
+	Chat       types.JID
+	Kind       string
+	Enabled    bool
+	Template   string
+	ProfilePic bool
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS greetings (
+			chat TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 0,
+			template TEXT NOT NULL DEFAULT '',
+			profile_pic INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (chat, kind)
+		)`,
+	)
+}
+
+func GetGreeting(chat types.JID, kind string) (*Greeting, error) {
+	g := &Greeting{Chat: chat, Kind: kind, Template: defaultGreetings[kind]}
+	var template string
+	err := DB.QueryRow(`SELECT enabled, template, profile_pic FROM greetings WHERE chat = ? AND kind = ?`, chat.String(), kind).
+		Scan(&g.Enabled, &template, &g.ProfilePic)
+	if err == sql.ErrNoRows {
+		return g, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if template != "" {
+		g.Template = template
+	}
+	return g, nil
+}
+
+func SaveGreeting(g *Greeting) error {
+	template := g.Template
+	if template == defaultGreetings[g.Kind] {
+		template = ""
+	}
+	_, err := DB.Exec(`INSERT OR REPLACE INTO greetings (chat, kind, enabled, template, profile_pic) VALUES (?, ?, ?, ?, ?)`,
+		g.Chat.String(), g.Kind, g.Enabled, template, g.ProfilePic)
+	return err
+}
+
+func RenderGreeting(template string, user types.JID, info *types.GroupInfo) string {
+	replacer := strings.NewReplacer(
+		"@user", "@"+user.User,
+		"{group}", info.Name,
+		"{count}", fmt.Sprint(len(info.Participants)),
+		"{desc}", info.Topic,
+	)
+	return replacer.Replace(template)
+}
+
+func HandleGreetings(client *whatsmeow.Client, evt *events.GroupInfo) {
+	if len(evt.Join) == 0 && len(evt.Leave) == 0 {
+		return
+	}
+
+	info, err := GetGroupInfo(client, evt.JID)
+	if err != nil {
+		return
+	}
+
+	sendGreetings(client, info, GreetingWelcome, evt.Join)
+	sendGreetings(client, info, GreetingGoodbye, evt.Leave)
+}
+
+func sendGreetings(client *whatsmeow.Client, info *types.GroupInfo, kind string, users []types.JID) {
+	if len(users) == 0 {
+		return
+	}
+
+	g, err := GetGreeting(info.JID, kind)
+	if err != nil || !g.Enabled {
+		return
+	}
+
+	chat := &Message{Client: client, Chat: info.JID}
+	for _, user := range users {
+		if IsOwnJID(client, user) {
+			continue
+		}
+
+		text := RenderGreeting(g.Template, user, info)
+		opts := SendOptions{Mentions: []types.JID{user}}
+
+		if g.ProfilePic {
+			if pic := profilePicture(client, user, info.JID); pic != nil {
+				opts.Caption = text
+				if _, err := chat.Send(MediaImage, pic, opts); err == nil {
+					continue
+				}
+			}
+		}
+		chat.Send(MediaText, text, opts)
+	}
+}
+
+func profilePicture(client *whatsmeow.Client, jids ...types.JID) []byte {
+	for _, jid := range jids {
+		pic, err := client.GetProfilePictureInfo(context.Background(), jid, &whatsmeow.GetProfilePictureParams{})
+		if err != nil || pic == nil || pic.URL == "" {
+			continue
+		}
+		data, err := getBuffer(pic.URL)
+		if err == nil {
+			return data
+		}
+	}
+	return nil
+}
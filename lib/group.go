@@ -0,0 +1,182 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const groupCacheTTL = 30 * time.Minute
+
+type groupEntry struct {
+	info    *types.GroupInfo
+	fetched time.Time
+}
+
+var (
+	groupCache = make(map[types.JID]groupEntry)
+	groupMutex sync.RWMutex
+)
+
+func GetGroupInfo(client *whatsmeow.Client, jid types.JID) (*types.GroupInfo, error) {
+	if jid.Server != types.GroupServer {
+		return nil, fmt.Errorf("%s is not a group", jid.String())
+	}
+
+	groupMutex.RLock()
+	entry, ok := groupCache[jid]
+	groupMutex.RUnlock()
+	if ok && time.Since(entry.fetched) < groupCacheTTL {
+		return entry.info, nil
+	}
+
+	info, err := client.GetGroupInfo(context.Background(), jid)
+	if err != nil {
+		return nil, err
+	}
+	CacheGroup(info)
+	return info, nil
+}
+
+func CacheGroup(info *types.GroupInfo) {
+	if info == nil || info.JID.IsEmpty() {
+		return
+	}
+	groupMutex.Lock()
+	groupCache[info.JID] = groupEntry{info: info, fetched: time.Now()}
+	groupMutex.Unlock()
+}
+
+func InvalidateGroup(jid types.JID) {
+	groupMutex.Lock()
+	delete(groupCache, jid)
+	groupMutex.Unlock()
+}
+
+func HandleGroupEvent(evt interface{}) {
+	switch v := evt.(type) {
+	case *events.GroupInfo:
+		InvalidateGroup(v.JID)
+	case *events.JoinedGroup:
+		info := v.GroupInfo
+		CacheGroup(&info)
+	}
+}
+
+func GroupAdmins(info *types.GroupInfo) []types.JID {
+	var admins []types.JID
+	for _, p := range info.Participants {
+		if p.IsAdmin || p.IsSuperAdmin {
+			admins = append(admins, p.JID)
+		}
+	}
+	return admins
+}
+
+func IsGroupAdmin(client *whatsmeow.Client, info *types.GroupInfo, jid types.JID) bool {
+	if info == nil || jid.IsEmpty() {
+		return false
+	}
+	for _, p := range info.Participants {
+		if !p.IsAdmin && !p.IsSuperAdmin {
+			continue
+		}
+		if SameUser(client, p.JID, jid) || SameUser(client, p.PhoneNumber, jid) || SameUser(client, p.LID, jid) {
+			return true
+		}
+	}
+	return false
+}
+
+func ParticipantJID(client *whatsmeow.Client, info *types.GroupInfo, jid types.JID) types.JID {
+	jid = jid.ToNonAD()
+	if info == nil {
+		return jid
+	}
+	for _, p := range info.Participants {
+		if SameUser(client, p.JID, jid) || SameUser(client, p.PhoneNumber, jid) || SameUser(client, p.LID, jid) {
+			return p.JID
+		}
+	}
+	if info.AddressingMode == types.AddressingModeLID {
+		return ToLID(client, jid)
+	}
+	return ToPN(client, jid)
+}
+
+func UpdateParticipants(client *whatsmeow.Client, group types.JID, users []types.JID, action whatsmeow.ParticipantChange) ([]types.GroupParticipant, error) {
+	info, _ := GetGroupInfo(client, group)
+	jids := make([]types.JID, len(users))
+	for i, user := range users {
+		jids[i] = ParticipantJID(client, info, user)
+	}
+	results, err := client.UpdateGroupParticipants(context.Background(), group, jids, action)
+	if err == nil {
+		InvalidateGroup(group)
+	}
+	return results, err
+}
+
+// MemberKey returns a stable key for user in group. LIDs are resolved to the
+// phone number through the participant list when the store has no mapping, so
+// the same member gets the same key whichever address an event used.
+func MemberKey(client *whatsmeow.Client, group, user types.JID) string {
+	user = ToPN(client, user)
+	if user.Server != types.HiddenUserServer {
+		return user.String()
+	}
+	if info, err := GetGroupInfo(client, group); err == nil {
+		for _, p := range info.Participants {
+			if !SameUser(client, p.JID, user) && !SameUser(client, p.LID, user) {
+				continue
+			}
+			if !p.PhoneNumber.IsEmpty() {
+				return p.PhoneNumber.ToNonAD().String()
+			}
+			if p.JID.Server == types.DefaultUserServer {
+				return p.JID.ToNonAD().String()
+			}
+		}
+	}
+	return user.String()
+}
+
+// RemoveParticipant removes user from group and reports a failure for that
+// participant as an error, not just a failure of the whole request.
+func RemoveParticipant(client *whatsmeow.Client, group, user types.JID) error {
+	results, err := UpdateParticipants(client, group, []types.JID{user}, whatsmeow.ParticipantChangeRemove)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Error != 0 {
+			return fmt.Errorf("server returned error %d", result.Error)
+		}
+	}
+	return nil
+}
+
+func (m *Message) GroupInfo() (*types.GroupInfo, error) {
+	return GetGroupInfo(m.Client, m.Chat)
+}
+
+func (m *Message) IsAdmin() bool {
+	info, err := m.GroupInfo()
+	if err != nil {
+		return false
+	}
+	return IsGroupAdmin(m.Client, info, m.Sender)
+}
+
+func (m *Message) IsBotAdmin() bool {
+	info, err := m.GroupInfo()
+	if err != nil || m.Client.Store.ID == nil {
+		return false
+	}
+	return IsGroupAdmin(m.Client, info, *m.Client.Store.ID) || IsGroupAdmin(m.Client, info, m.Client.Store.GetLID())
+}
@@ -0,0 +1,105 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func ParseJID(s string) types.JID {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return types.EmptyJID
+	}
+	if !strings.Contains(s, "@") {
+		return types.NewJID(strings.TrimPrefix(s, "+"), types.DefaultUserServer)
+	}
+	jid, err := types.ParseJID(s)
+	if err != nil {
+		return types.EmptyJID
+	}
+	return jid
+}
+
+func ToPN(client *whatsmeow.Client, jid types.JID) types.JID {
+	jid = jid.ToNonAD()
+	if jid.Server != types.HiddenUserServer || client == nil {
+		return jid
+	}
+	if own := client.Store.GetLID(); !own.IsEmpty() && own.User == jid.User && client.Store.ID != nil {
+		return client.Store.ID.ToNonAD()
+	}
+	pn, err := client.Store.LIDs.GetPNForLID(context.Background(), jid)
+	if err != nil || pn.IsEmpty() {
+		return jid
+	}
+	return pn.ToNonAD()
+}
+
+func ToLID(client *whatsmeow.Client, jid types.JID) types.JID {
+	jid = jid.ToNonAD()
+	if jid.Server != types.DefaultUserServer || client == nil {
+		return jid
+	}
+	if client.Store.ID != nil && client.Store.ID.User == jid.User {
+		if own := client.Store.GetLID(); !own.IsEmpty() {
+			return own.ToNonAD()
+		}
+	}
+	lid, err := client.Store.LIDs.GetLIDForPN(context.Background(), jid)
+	if err != nil || lid.IsEmpty() {
+		return jid
+	}
+	return lid.ToNonAD()
+}
+
+func SameUser(client *whatsmeow.Client, a, b types.JID) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+	if a.User == b.User && a.Server == b.Server {
+		return true
+	}
+	return ToPN(client, a) == ToPN(client, b)
+}
+
+func IsOwnJID(client *whatsmeow.Client, jid types.JID) bool {
+	if client == nil || client.Store.ID == nil || jid.IsEmpty() {
+		return false
+	}
+	if jid.User == client.Store.ID.User && jid.Server == types.DefaultUserServer {
+		return true
+	}
+	own := client.Store.GetLID()
+	return !own.IsEmpty() && jid.User == own.User && jid.Server == types.HiddenUserServer
+}
+
+func IsSudo(client *whatsmeow.Client, jid types.JID) bool {
+	if jid.IsEmpty() {
+		return false
+	}
+	if IsOwnJID(client, jid) {
+		return true
+	}
+	pn := ToPN(client, jid)
+	for _, sudo := range strings.Split(Config.SUDO, ",") {
+		sudo = strings.TrimSpace(sudo)
+		if sudo == "" {
+			continue
+		}
+		if ParseJID(sudo).User == pn.User && pn.Server == types.DefaultUserServer {
+			return true
+		}
+	}
+	return false
+}
+
+func OwnerJID(client *whatsmeow.Client) types.JID {
+	sudo := strings.TrimSpace(strings.Split(Config.SUDO, ",")[0])
+	if sudo == "" && client != nil && client.Store.ID != nil {
+		sudo = client.Store.ID.User
+	}
+	return ParseJID(sudo)
+}
@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type Location struct {
+	Latitude         float64
+	Longitude        float64
+	Name             string
+	Address          string
+	URL              string
+	Comment          string
+	AccuracyInMeters uint32
+	Thumbnail        []byte
+}
+
+type LiveLocation struct {
+	Latitude         float64
+	Longitude        float64
+	Caption          string
+	AccuracyInMeters uint32
+	SpeedInMps       float32
+	Heading          uint32
+	SequenceNumber   int64
+	TimeOffset       uint32
+	Thumbnail        []byte
+}
+
+func (m *Message) sendLocation(loc Location, opts SendOptions) (*Message, error) {
+	msg := &waE2E.Message{
+		LocationMessage: &waE2E.LocationMessage{
+			DegreesLatitude:  proto.Float64(loc.Latitude),
+			DegreesLongitude: proto.Float64(loc.Longitude),
+			JPEGThumbnail:    loc.Thumbnail,
+		},
+	}
+
+	if loc.Name != "" {
+		msg.LocationMessage.Name = proto.String(loc.Name)
+	}
+	if loc.Address != "" {
+		msg.LocationMessage.Address = proto.String(loc.Address)
+	}
+	if loc.URL != "" {
+		msg.LocationMessage.URL = proto.String(loc.URL)
+	}
+	if loc.Comment != "" {
+		msg.LocationMessage.Comment = proto.String(loc.Comment)
+	}
+	if loc.AccuracyInMeters > 0 {
+		msg.LocationMessage.AccuracyInMeters = proto.Uint32(loc.AccuracyInMeters)
+	}
+
+	msg.LocationMessage.ContextInfo = m.contextInfo(loc.Comment, opts)
+
+	return m.deliver(msg, opts)
+}
+
+func (m *Message) sendLiveLocation(loc LiveLocation, opts SendOptions) (*Message, error) {
+	msg := &waE2E.Message{
+		LiveLocationMessage: &waE2E.LiveLocationMessage{
+			DegreesLatitude:  proto.Float64(loc.Latitude),
+			DegreesLongitude: proto.Float64(loc.Longitude),
+			SequenceNumber:   proto.Int64(loc.SequenceNumber),
+			JPEGThumbnail:    loc.Thumbnail,
+		},
+	}
+
+	if loc.Caption != "" {
+		msg.LiveLocationMessage.Caption = proto.String(loc.Caption)
+	}
+	if loc.AccuracyInMeters > 0 {
+		msg.LiveLocationMessage.AccuracyInMeters = proto.Uint32(loc.AccuracyInMeters)
+	}
+	if loc.SpeedInMps > 0 {
+		msg.LiveLocationMessage.SpeedInMps = proto.Float32(loc.SpeedInMps)
+	}
+	if loc.Heading > 0 {
+		msg.LiveLocationMessage.DegreesClockwiseFromMagneticNorth = proto.Uint32(loc.Heading)
+	}
+	if loc.TimeOffset > 0 {
+		msg.LiveLocationMessage.TimeOffset = proto.Uint32(loc.TimeOffset)
+	}
+
+	msg.LiveLocationMessage.ContextInfo = m.contextInfo(loc.Caption, opts)
+
+	return m.deliver(msg, opts)
+}
+
+func parseLocation(msg *waE2E.LocationMessage) *Location {
+	if msg == nil {
+		return nil
+	}
+	return &Location{
+		Latitude:         msg.GetDegreesLatitude(),
+		Longitude:        msg.GetDegreesLongitude(),
+		Name:             msg.GetName(),
+		Address:          msg.GetAddress(),
+		URL:              msg.GetURL(),
+		Comment:          msg.GetComment(),
+		AccuracyInMeters: msg.GetAccuracyInMeters(),
+		Thumbnail:        msg.GetJPEGThumbnail(),
+	}
+}
+
+func parseLiveLocation(msg *waE2E.LiveLocationMessage) *LiveLocation {
+	if msg == nil {
+		return nil
+	}
+	return &LiveLocation{
+		Latitude:         msg.GetDegreesLatitude(),
+		Longitude:        msg.GetDegreesLongitude(),
+		Caption:          msg.GetCaption(),
+		AccuracyInMeters: msg.GetAccuracyInMeters(),
+		SpeedInMps:       msg.GetSpeedInMps(),
+		Heading:          msg.GetDegreesClockwiseFromMagneticNorth(),
+		SequenceNumber:   msg.GetSequenceNumber(),
+		TimeOffset:       msg.GetTimeOffset(),
+		Thumbnail:        msg.GetJPEGThumbnail(),
+	}
+}
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"go.mau.fi/whatsmeow"
@@ -14,8 +15,10 @@ import (
 type Message struct {
 	Client        *whatsmeow.Client
 	Data          *events.Message
+	Inner         *waE2E.Message
 	ID            string
 	Sender        types.JID
+	SenderPN      types.JID
 	FromMe        bool
 	Chat          types.JID
 	Type          string
@@ -27,6 +30,26 @@ type Message struct {
 	PushName      string
 	MentionedJid  []types.JID
 	Quoted        *ReplyMessage
+	Location      *Location
+	LiveLocation  *LiveLocation
+	Contacts      []Contact
+	PollUpdate    *PollUpdate
+	Reaction      *Reaction
+	IsEdit        bool
+	EditedID      string
+	IsRevoke      bool
+	RevokedID     string
+	IsViewOnce    bool
+
+	reaction string
+}
+
+type MessageKey struct {
+	Chat        types.JID
+	ID          string
+	Participant types.JID
+	FromMe      bool
+	Message     *waE2E.Message
 }
 
 func NewMessage(client *whatsmeow.Client, evt *events.Message) *Message {
@@ -42,28 +65,41 @@ func NewMessage(client *whatsmeow.Client, evt *events.Message) *Message {
 		PushName: evt.Info.PushName,
 	}
 
-	msg.Type = getContentType(evt.Message)
-	msg.Text = getMessageText(evt.Message)
+	msg.Inner = UnwrapMessage(evt.Message)
+	msg.Type = getContentType(msg.Inner)
+	msg.Text = getMessageText(msg.Inner)
+
+	if protocol := evt.Message.GetProtocolMessage(); protocol.GetType() == waE2E.ProtocolMessage_MESSAGE_EDIT && protocol.GetEditedMessage() != nil {
+		msg.IsEdit = true
+		msg.EditedID = protocol.GetKey().GetID()
+	} else if protocol.GetType() == waE2E.ProtocolMessage_REVOKE {
+		msg.IsRevoke = true
+		msg.RevokedID = protocol.GetKey().GetID()
+	}
+	msg.IsViewOnce = evt.IsViewOnce || isViewOnce(evt.Message)
+	msg.Location = parseLocation(msg.Inner.GetLocationMessage())
+	msg.LiveLocation = parseLiveLocation(msg.Inner.GetLiveLocationMessage())
+	msg.Contacts = parseContacts(msg.Inner)
+	msg.Reaction = parseReaction(client, evt.Info, msg.Inner.GetReactionMessage())
 	msg.IsBot = strings.HasPrefix(evt.Info.ID, "BAE5") && len(evt.Info.ID) == 16
 
-	sudos := strings.Split(Config.SUDO, ",")
-	sudoJids := []string{client.Store.ID.User}
-	sudoJids = append(sudoJids, sudos...)
-	
-	for _, sudo := range sudoJids {
-		if sudo != "" && sudo+"@s.whatsapp.net" == msg.Sender.String() {
-			msg.IsSudo = true
-			break
+	msg.SenderPN = msg.Sender.ToNonAD()
+	if msg.Sender.Server == types.HiddenUserServer {
+		if evt.Info.SenderAlt.Server == types.DefaultUserServer {
+			msg.SenderPN = evt.Info.SenderAlt.ToNonAD()
+		} else {
+			msg.SenderPN = ToPN(client, msg.Sender)
 		}
 	}
+	msg.IsSudo = IsSudo(client, msg.SenderPN)
 
-	if evt.Message.ExtendedTextMessage != nil && evt.Message.ExtendedTextMessage.ContextInfo != nil {
-		if evt.Message.ExtendedTextMessage.ContextInfo.MentionedJID != nil {
-			for _, jid := range evt.Message.ExtendedTextMessage.ContextInfo.MentionedJID {
-				msg.MentionedJid = append(msg.MentionedJid, types.NewJID(jid, types.DefaultUserServer))
+	if ctx := getContextInfo(msg.Inner); ctx != nil {
+		for _, jid := range ctx.MentionedJID {
+			if parsed := ParseJID(jid); !parsed.IsEmpty() {
+				msg.MentionedJid = append(msg.MentionedJid, parsed)
 			}
 		}
-		if evt.Message.ExtendedTextMessage.ContextInfo.QuotedMessage != nil {
+		if ctx.QuotedMessage != nil {
 			msg.Quoted = NewReplyMessage(client, evt)
 		}
 	}
@@ -72,7 +108,7 @@ func NewMessage(client *whatsmeow.Client, evt *events.Message) *Message {
 }
 
 func (m *Message) Reply(text string) (*Message, error) {
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, &waE2E.Message{
+	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
 			Text: proto.String(text),
 			ContextInfo: &waE2E.ContextInfo{
@@ -81,10 +117,12 @@ func (m *Message) Reply(text string) (*Message, error) {
 				QuotedMessage: m.Data.Message,
 			},
 		},
-	})
+	}
+	response, err := m.Client.SendMessage(context.Background(), m.Chat, msg)
 	if err != nil {
 		return nil, err
 	}
+	StoreSent(m.Chat, response.ID, msg, response.Timestamp)
 
 	return &Message{
 		Client: m.Client,
@@ -94,57 +132,262 @@ func (m *Message) Reply(text string) (*Message, error) {
 	}, nil
 }
 
+func (m *Message) Key() MessageKey {
+	key := MessageKey{
+		Chat:        m.Chat,
+		ID:          m.ID,
+		Participant: m.Sender,
+		FromMe:      m.FromMe,
+	}
+	if m.Data != nil {
+		key.Message = m.Data.Message
+	}
+	if key.Participant.IsEmpty() && m.FromMe && m.Client != nil && m.Client.Store.ID != nil {
+		key.Participant = m.Client.Store.ID.ToNonAD()
+	}
+	return key
+}
+
+func (m *Message) Edit(text string) (*Message, error) {
+	if !m.FromMe {
+		return nil, fmt.Errorf("can only edit messages sent by the bot")
+	}
+
+	_, err := m.Client.SendMessage(context.Background(), m.Chat, m.Client.BuildEdit(m.Chat, m.ID, &waE2E.Message{
+		Conversation: proto.String(text),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	m.Text = text
+	return m, nil
+}
+
 func (m *Message) Delete() error {
-	_, err := m.Client.SendMessage(context.Background(), m.Chat, m.Client.BuildRevoke(m.Chat, types.EmptyJID, m.ID))
+	sender := types.EmptyJID
+	if !m.FromMe {
+		sender = m.Sender
+	}
+	_, err := m.Client.SendMessage(context.Background(), m.Chat, m.Client.BuildRevoke(m.Chat, sender, m.ID))
 	return err
 }
 
+func UnwrapMessage(msg *waE2E.Message) *waE2E.Message {
+	for msg != nil {
+		var inner *waE2E.Message
+		switch {
+		case msg.GetDeviceSentMessage().GetMessage() != nil:
+			inner = msg.GetDeviceSentMessage().GetMessage()
+		case msg.GetEphemeralMessage().GetMessage() != nil:
+			inner = msg.GetEphemeralMessage().GetMessage()
+		case msg.GetViewOnceMessage().GetMessage() != nil:
+			inner = msg.GetViewOnceMessage().GetMessage()
+		case msg.GetViewOnceMessageV2().GetMessage() != nil:
+			inner = msg.GetViewOnceMessageV2().GetMessage()
+		case msg.GetViewOnceMessageV2Extension().GetMessage() != nil:
+			inner = msg.GetViewOnceMessageV2Extension().GetMessage()
+		case msg.GetDocumentWithCaptionMessage().GetMessage() != nil:
+			inner = msg.GetDocumentWithCaptionMessage().GetMessage()
+		case msg.GetEditedMessage().GetMessage() != nil:
+			inner = msg.GetEditedMessage().GetMessage()
+		case msg.GetBotInvokeMessage().GetMessage() != nil:
+			inner = msg.GetBotInvokeMessage().GetMessage()
+		case msg.GetLottieStickerMessage().GetMessage() != nil:
+			inner = msg.GetLottieStickerMessage().GetMessage()
+		case msg.GetGroupMentionedMessage().GetMessage() != nil:
+			inner = msg.GetGroupMentionedMessage().GetMessage()
+		case msg.GetPollCreationMessageV4().GetMessage() != nil:
+			inner = msg.GetPollCreationMessageV4().GetMessage()
+		case msg.GetProtocolMessage().GetType() == waE2E.ProtocolMessage_MESSAGE_EDIT && msg.GetProtocolMessage().GetEditedMessage() != nil:
+			inner = msg.GetProtocolMessage().GetEditedMessage()
+		}
+		if inner == nil {
+			return msg
+		}
+		msg = inner
+	}
+	return msg
+}
+
 func getContentType(msg *waE2E.Message) string {
+	msg = UnwrapMessage(msg)
 	if msg == nil {
 		return ""
 	}
-	if msg.Conversation != nil {
+	switch {
+	case msg.Conversation != nil:
 		return "conversation"
-	}
-	if msg.ExtendedTextMessage != nil {
+	case msg.ExtendedTextMessage != nil:
 		return "extendedTextMessage"
-	}
-	if msg.ImageMessage != nil {
+	case msg.ImageMessage != nil:
 		return "imageMessage"
-	}
-	if msg.VideoMessage != nil {
+	case msg.VideoMessage != nil:
 		return "videoMessage"
-	}
-	if msg.AudioMessage != nil {
+	case msg.PtvMessage != nil:
+		return "ptvMessage"
+	case msg.AudioMessage != nil:
 		return "audioMessage"
-	}
-	if msg.DocumentMessage != nil {
+	case msg.DocumentMessage != nil:
 		return "documentMessage"
-	}
-	if msg.StickerMessage != nil {
+	case msg.StickerMessage != nil:
 		return "stickerMessage"
+	case msg.LocationMessage != nil:
+		return "locationMessage"
+	case msg.LiveLocationMessage != nil:
+		return "liveLocationMessage"
+	case msg.ContactMessage != nil:
+		return "contactMessage"
+	case msg.ContactsArrayMessage != nil:
+		return "contactsArrayMessage"
+	case getPollCreation(msg) != nil:
+		return "pollCreationMessage"
+	case msg.PollUpdateMessage != nil:
+		return "pollUpdateMessage"
+	case msg.ReactionMessage != nil:
+		return "reactionMessage"
+	case msg.ButtonsResponseMessage != nil:
+		return "buttonsResponseMessage"
+	case msg.ListResponseMessage != nil:
+		return "listResponseMessage"
+	case msg.TemplateButtonReplyMessage != nil:
+		return "templateButtonReplyMessage"
+	case msg.InteractiveResponseMessage != nil:
+		return "interactiveResponseMessage"
+	case msg.ButtonsMessage != nil:
+		return "buttonsMessage"
+	case msg.ListMessage != nil:
+		return "listMessage"
+	case msg.TemplateMessage != nil:
+		return "templateMessage"
+	case msg.InteractiveMessage != nil:
+		return "interactiveMessage"
+	case msg.GetProtocolMessage().GetType() == waE2E.ProtocolMessage_REVOKE:
+		return "revokeMessage"
+	case msg.ProtocolMessage != nil:
+		return "protocolMessage"
 	}
 	return ""
 }
 
 func getMessageText(msg *waE2E.Message) string {
+	msg = UnwrapMessage(msg)
 	if msg == nil {
 		return ""
 	}
-	if msg.Conversation != nil {
-		return *msg.Conversation
-	}
-	if msg.ExtendedTextMessage != nil && msg.ExtendedTextMessage.Text != nil {
-		return *msg.ExtendedTextMessage.Text
+	switch {
+	case msg.Conversation != nil:
+		return msg.GetConversation()
+	case msg.ExtendedTextMessage != nil:
+		return msg.ExtendedTextMessage.GetText()
+	case msg.ImageMessage != nil:
+		return msg.ImageMessage.GetCaption()
+	case msg.VideoMessage != nil:
+		return msg.VideoMessage.GetCaption()
+	case msg.PtvMessage != nil:
+		return msg.PtvMessage.GetCaption()
+	case msg.DocumentMessage != nil:
+		return msg.DocumentMessage.GetCaption()
+	case msg.LocationMessage != nil:
+		return msg.LocationMessage.GetComment()
+	case msg.LiveLocationMessage != nil:
+		return msg.LiveLocationMessage.GetCaption()
+	case getPollCreation(msg) != nil:
+		return getPollCreation(msg).GetName()
+	case msg.ButtonsResponseMessage != nil:
+		return firstNonEmpty(msg.ButtonsResponseMessage.GetSelectedButtonID(), msg.ButtonsResponseMessage.GetSelectedDisplayText())
+	case msg.ListResponseMessage != nil:
+		return firstNonEmpty(msg.ListResponseMessage.GetSingleSelectReply().GetSelectedRowID(), msg.ListResponseMessage.GetTitle())
+	case msg.TemplateButtonReplyMessage != nil:
+		return firstNonEmpty(msg.TemplateButtonReplyMessage.GetSelectedID(), msg.TemplateButtonReplyMessage.GetSelectedDisplayText())
+	case msg.InteractiveResponseMessage != nil:
+		return firstNonEmpty(msg.InteractiveResponseMessage.GetNativeFlowResponseMessage().GetParamsJSON(), msg.InteractiveResponseMessage.GetBody().GetText())
+	case msg.ButtonsMessage != nil:
+		return msg.ButtonsMessage.GetContentText()
+	case msg.ListMessage != nil:
+		return firstNonEmpty(msg.ListMessage.GetDescription(), msg.ListMessage.GetTitle())
+	case msg.TemplateMessage != nil:
+		return msg.TemplateMessage.GetHydratedTemplate().GetHydratedContentText()
+	case msg.InteractiveMessage != nil:
+		return msg.InteractiveMessage.GetBody().GetText()
 	}
-	if msg.ImageMessage != nil && msg.ImageMessage.Caption != nil {
-		return *msg.ImageMessage.Caption
+	return ""
+}
+
+func getContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	msg = UnwrapMessage(msg)
+	if msg == nil {
+		return nil
 	}
-	if msg.VideoMessage != nil && msg.VideoMessage.Caption != nil {
-		return *msg.VideoMessage.Caption
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		return msg.ExtendedTextMessage.ContextInfo
+	case msg.ImageMessage != nil:
+		return msg.ImageMessage.ContextInfo
+	case msg.VideoMessage != nil:
+		return msg.VideoMessage.ContextInfo
+	case msg.PtvMessage != nil:
+		return msg.PtvMessage.ContextInfo
+	case msg.AudioMessage != nil:
+		return msg.AudioMessage.ContextInfo
+	case msg.DocumentMessage != nil:
+		return msg.DocumentMessage.ContextInfo
+	case msg.StickerMessage != nil:
+		return msg.StickerMessage.ContextInfo
+	case msg.LocationMessage != nil:
+		return msg.LocationMessage.ContextInfo
+	case msg.LiveLocationMessage != nil:
+		return msg.LiveLocationMessage.ContextInfo
+	case msg.ContactMessage != nil:
+		return msg.ContactMessage.ContextInfo
+	case msg.ContactsArrayMessage != nil:
+		return msg.ContactsArrayMessage.ContextInfo
+	case getPollCreation(msg) != nil:
+		return getPollCreation(msg).ContextInfo
+	case msg.ButtonsResponseMessage != nil:
+		return msg.ButtonsResponseMessage.ContextInfo
+	case msg.ListResponseMessage != nil:
+		return msg.ListResponseMessage.ContextInfo
+	case msg.TemplateButtonReplyMessage != nil:
+		return msg.TemplateButtonReplyMessage.ContextInfo
+	case msg.InteractiveResponseMessage != nil:
+		return msg.InteractiveResponseMessage.ContextInfo
+	case msg.ButtonsMessage != nil:
+		return msg.ButtonsMessage.ContextInfo
+	case msg.ListMessage != nil:
+		return msg.ListMessage.ContextInfo
+	case msg.InteractiveMessage != nil:
+		return msg.InteractiveMessage.ContextInfo
 	}
-	if msg.DocumentMessage != nil && msg.DocumentMessage.Caption != nil {
-		return *msg.DocumentMessage.Caption
+	return nil
+}
+
+func setContextInfo(msg *waE2E.Message, ctx *waE2E.ContextInfo) bool {
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		msg.ExtendedTextMessage.ContextInfo = ctx
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = ctx
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = ctx
+	case msg.PtvMessage != nil:
+		msg.PtvMessage.ContextInfo = ctx
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = ctx
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = ctx
+	case msg.StickerMessage != nil:
+		msg.StickerMessage.ContextInfo = ctx
+	case msg.LocationMessage != nil:
+		msg.LocationMessage.ContextInfo = ctx
+	case msg.LiveLocationMessage != nil:
+		msg.LiveLocationMessage.ContextInfo = ctx
+	case msg.ContactMessage != nil:
+		msg.ContactMessage.ContextInfo = ctx
+	case msg.ContactsArrayMessage != nil:
+		msg.ContactsArrayMessage.ContextInfo = ctx
+	default:
+		return false
 	}
-	return ""
-}
\ No newline at end of file
+	return true
+}
@@ -0,0 +1,18 @@
+package lib
+
+type Middleware func(*Message) bool
+
+var middlewares []Middleware
+
+func Use(middleware Middleware) {
+	middlewares = append(middlewares, middleware)
+}
+
+func RunMiddleware(m *Message) bool {
+	for _, middleware := range middlewares {
+		if !middleware(m) {
+			return false
+		}
+	}
+	return true
+}
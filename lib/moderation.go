@@ -0,0 +1,343 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	FilterAntiLink    = "antilink"
+	FilterAntiSpam    = "antispam"
+	FilterAntiWord    = "antiword"
+	FilterAntiForward = "antiforward"
+
+	ActionWarn   = "warn"
+	ActionDelete = "delete"
+	ActionKick   = "kick"
+)
+
+var Filters = []string{FilterAntiLink, FilterAntiSpam, FilterAntiWord, FilterAntiForward}
+
+var commonTLDs = map[string]bool{
+	"com": true, "net": true, "org": true, "info": true, "biz": true, "io": true, "co": true,
+	"me": true, "app": true, "dev": true, "xyz": true, "site": true, "online": true, "store": true,
+	"shop": true, "link": true, "live": true, "tv": true, "gg": true, "ly": true, "to": true,
+	"cc": true, "ws": true, "edu": true, "gov": true, "in": true, "uk": true, "us": true,
+	"ca": true, "au": true, "de": true, "fr": true, "es": true, "it": true, "nl": true,
+	"ru": true, "br": true, "id": true, "pk": true, "ng": true, "za": true, "ke": true,
+	"lk": true, "bd": true, "my": true, "ph": true, "sg": true, "tk": true, "ml": true,
+	"ga": true, "cf": true, "gq": true, "top": true, "club": true, "icu": true, "cn": true,
+	"jp": true, "kr": true, "tr": true, "ir": true, "ae": true, "sa": true, "eg": true,
+}
+
+type ModerationSetting struct {
+	Chat    types.JID
+	Filter  string
+	Enabled bool
+	Action  string
+	Values  []string
+}
+
+var (
+	linkRegex   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+	domainRegex = regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)+([a-z]{2,})\b(?:/[^\s]*)?`)
+	inviteRegex = regexp.MustCompile(`(?i)chat\.whatsapp\.com/(?:invite/)?[a-z0-9]{20,24}`)
+
+	moderationCache = make(map[types.JID]*moderationState)
+	moderationMutex sync.RWMutex
+
+	spamTracker   = make(map[string][]time.Time)
+	spamMutex     sync.Mutex
+	spamLastPrune time.Time
+)
+
+const spamIdle = 10 * time.Minute
+
+type moderationState struct {
+	settings map[string]*ModerationSetting
+	badWords *regexp.Regexp
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS moderation (
+			chat TEXT NOT NULL,
+			filter TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 0,
+			action TEXT NOT NULL DEFAULT 'delete',
+			vals TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (chat, filter)
+		)`,
+	)
+}
+
+func defaultModeration(chat types.JID, filter string) *ModerationSetting {
+	s := &ModerationSetting{Chat: chat, Filter: filter, Action: ActionDelete}
+	if filter == FilterAntiSpam {
+		s.Values = []string{"5", "5"}
+	}
+	return s
+}
+
+func GetModeration(chat types.JID) (map[string]*ModerationSetting, error) {
+	state, err := loadModeration(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make(map[string]*ModerationSetting, len(state.settings))
+	for filter, s := range state.settings {
+		c := *s
+		c.Values = append([]string(nil), s.Values...)
+		copies[filter] = &c
+	}
+	return copies, nil
+}
+
+func loadModeration(chat types.JID) (*moderationState, error) {
+	moderationMutex.RLock()
+	state, ok := moderationCache[chat]
+	moderationMutex.RUnlock()
+	if ok {
+		return state, nil
+	}
+
+	settings := make(map[string]*ModerationSetting)
+	for _, filter := range Filters {
+		settings[filter] = defaultModeration(chat, filter)
+	}
+
+	rows, err := DB.Query(`SELECT filter, enabled, action, vals FROM moderation WHERE chat = ?`, chat.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := &ModerationSetting{Chat: chat}
+		var vals string
+		if err := rows.Scan(&s.Filter, &s.Enabled, &s.Action, &vals); err != nil {
+			return nil, err
+		}
+		if vals != "" {
+			s.Values = strings.Split(vals, ",")
+		}
+		settings[s.Filter] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	words := append(strings.Split(Config.BAD_WORDS, ","), settings[FilterAntiWord].Values...)
+	state = &moderationState{settings: settings, badWords: compileBadWords(words)}
+
+	moderationMutex.Lock()
+	moderationCache[chat] = state
+	moderationMutex.Unlock()
+	return state, nil
+}
+
+func SaveModeration(s *ModerationSetting) error {
+	_, err := DB.Exec(`INSERT OR REPLACE INTO moderation (chat, filter, enabled, action, vals) VALUES (?, ?, ?, ?, ?)`,
+		s.Chat.String(), s.Filter, s.Enabled, s.Action, strings.Join(s.Values, ","))
+	if err != nil {
+		return err
+	}
+	moderationMutex.Lock()
+	delete(moderationCache, s.Chat)
+	moderationMutex.Unlock()
+	return nil
+}
+
+func Moderate(m *Message) bool {
+	if !m.IsGroup || m.FromMe || m.IsSudo || m.IsRevoke || m.Data == nil {
+		return true
+	}
+
+	if IsMuted(m.Client, m.Chat, m.Sender) && !m.IsAdmin() {
+		if m.IsBotAdmin() {
+			m.Delete()
+		}
+		return false
+	}
+
+	state, err := loadModeration(m.Chat)
+	if err != nil {
+		return true
+	}
+	settings := state.settings
+
+	enabled := false
+	for _, s := range settings {
+		enabled = enabled || s.Enabled
+	}
+	if !enabled || m.IsAdmin() {
+		return true
+	}
+
+	for _, filter := range Filters {
+		s := settings[filter]
+		if !s.Enabled {
+			continue
+		}
+		if reason := checkFilter(m, s, state); reason != "" {
+			applyAction(m, s.Action, reason)
+			return false
+		}
+	}
+	return true
+}
+
+func checkFilter(m *Message, s *ModerationSetting, state *moderationState) string {
+	switch s.Filter {
+	case FilterAntiLink:
+		return checkLinks(m.Text, s.Values)
+	case FilterAntiSpam:
+		limit, window := 5, 5
+		if len(s.Values) == 2 {
+			if v, err := strconv.Atoi(s.Values[0]); err == nil && v > 0 {
+				limit = v
+			}
+			if v, err := strconv.Atoi(s.Values[1]); err == nil && v > 0 {
+				window = v
+			}
+		}
+		if isSpam(m, limit, time.Duration(window)*time.Second) {
+			return "spamming"
+		}
+	case FilterAntiWord:
+		if state.badWords != nil && state.badWords.MatchString(m.Text) {
+			return "using a banned word"
+		}
+	case FilterAntiForward:
+		if ctx := getContextInfo(m.Inner); ctx.GetIsForwarded() {
+			return "forwarding messages"
+		}
+	}
+	return ""
+}
+
+func checkLinks(text string, allowed []string) string {
+	if inviteRegex.MatchString(text) && !domainAllowed("chat.whatsapp.com", allowed) {
+		return "sending a group invite link"
+	}
+
+	links := linkRegex.FindAllString(text, -1)
+	for _, m := range domainRegex.FindAllStringSubmatchIndex(text, -1) {
+		if (m[0] > 0 && text[m[0]-1] == '@') || (m[1] < len(text) && text[m[1]] == '@') {
+			continue
+		}
+		if commonTLDs[strings.ToLower(text[m[2]:m[3]])] {
+			links = append(links, text[m[0]:m[1]])
+		}
+	}
+
+	for _, link := range links {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		u, err := url.Parse(link)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		if !domainAllowed(u.Hostname(), allowed) {
+			return "sending links"
+		}
+	}
+	return ""
+}
+
+func domainAllowed(host string, allowed []string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for _, domain := range allowed {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpam(m *Message, limit int, window time.Duration) bool {
+	key := m.Chat.String() + "|" + m.SenderPN.String()
+	now := time.Now()
+
+	spamMutex.Lock()
+	defer spamMutex.Unlock()
+
+	if now.Sub(spamLastPrune) > time.Minute {
+		for k, times := range spamTracker {
+			if len(times) == 0 || now.Sub(times[len(times)-1]) > spamIdle {
+				delete(spamTracker, k)
+			}
+		}
+		spamLastPrune = now
+	}
+
+	recent := spamTracker[key][:0]
+	for _, t := range spamTracker[key] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, now)
+	spamTracker[key] = recent
+
+	if len(recent) > limit {
+		delete(spamTracker, key)
+		return true
+	}
+	return false
+}
+
+func compileBadWords(words []string) *regexp.Regexp {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(?:` + strings.Join(quoted, "|") + `)(?:$|[^\p{L}\p{N}])`)
+}
+
+func applyAction(m *Message, action, reason string) {
+	group := &Message{Client: m.Client, Chat: m.Chat}
+	mentions := SendOptions{Mentions: []types.JID{m.Sender}}
+
+	switch action {
+	case ActionDelete:
+		if m.IsBotAdmin() {
+			m.Delete()
+		}
+	case ActionKick:
+		if !m.IsBotAdmin() {
+			return
+		}
+		m.Delete()
+		if err := RemoveParticipant(m.Client, m.Chat, m.Sender); err != nil {
+			group.Send(MediaText, fmt.Sprintf("_Failed to remove @%s for %s: %v_", m.Sender.User, reason, err), mentions)
+			return
+		}
+		group.Send(MediaText, fmt.Sprintf("_@%s was removed for %s_", m.Sender.User, reason), mentions)
+	case ActionWarn:
+		var issuer types.JID
+		if m.Client.Store.ID != nil {
+			issuer = m.Client.Store.ID.ToNonAD()
+		}
+		text := fmt.Sprintf("_@%s, you are not allowed to keep %s here_", m.Sender.User, reason)
+		if result, err := AddWarn(m.Client, m.Chat, m.Sender, issuer, reason); result != nil {
+			text = WarnText(m.Sender, result, reason, err)
+		}
+		group.Send(MediaText, text, mentions)
+	}
+}
@@ -0,0 +1,65 @@
+package lib
+
+import "testing"
+
+func TestCheckLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		allowed []string
+		flagged bool
+	}{
+		{"plain chat", "hello there, how are you?", nil, false},
+		{"file names", "check main.go and node.js, ask mr.smith", nil, false},
+		{"sentence end", "done.Next we go", nil, false},
+		{"scheme", "visit https://example.org/page", nil, true},
+		{"www", "go to www.example.xyz now", nil, true},
+		{"bare known tld", "try example.com", nil, true},
+		{"bare country tld", "see shop.co.uk for more", nil, true},
+		{"invite", "join chat.whatsapp.com/AbCdEfGhIjKlMnOpQrStUv", nil, true},
+		{"invite allowed", "join chat.whatsapp.com/AbCdEfGhIjKlMnOpQrStUv", []string{"chat.whatsapp.com"}, false},
+		{"allowlisted domain", "https://youtube.com/watch?v=1", []string{"youtube.com"}, false},
+		{"allowlisted subdomain", "https://m.youtube.com/watch?v=1", []string{"youtube.com"}, false},
+		{"allowlist suffix trick", "https://notyoutube.com", []string{"youtube.com"}, true},
+		{"email address", "mail me at user@example.com", nil, false},
+		{"dotted email address", "write to john.me@example.com", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkLinks(tt.text, tt.allowed) != ""; got != tt.flagged {
+				t.Errorf("checkLinks(%q) flagged = %v, want %v", tt.text, got, tt.flagged)
+			}
+		})
+	}
+}
+
+func TestBadWords(t *testing.T) {
+	re := compileBadWords([]string{"darn", " heck ", "", "бля", "a.b"})
+
+	tests := []struct {
+		text  string
+		match bool
+	}{
+		{"darn it", true},
+		{"DARN", true},
+		{"what the heck!", true},
+		{"darned", false},
+		{"undarn", false},
+		{"ну бля", true},
+		{"блять", false},
+		{"a.b", true},
+		{"axb", false},
+		{"clean message", false},
+	}
+
+	for _, tt := range tests {
+		if got := re.MatchString(tt.text); got != tt.match {
+			t.Errorf("bad word match %q = %v, want %v", tt.text, got, tt.match)
+		}
+	}
+
+	if compileBadWords([]string{"", " "}) != nil {
+		t.Error("compileBadWords with no words should return nil")
+	}
+}
@@ -0,0 +1,204 @@
+package lib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const noteMediaLimit = 5 << 20
+
+type Note struct {
+	Chat      types.JID
+	Name      string
+	Type      MediaType
+	Text      string
+	Mentions  []types.JID
+	Mimetype  string
+	FileName  string
+	PTT       bool
+	Media     []byte
+	Message   *waE2E.Message
+	CreatedAt time.Time
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS notes (
+			chat TEXT NOT NULL,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			text TEXT NOT NULL DEFAULT '',
+			mentions TEXT NOT NULL DEFAULT '',
+			mimetype TEXT NOT NULL DEFAULT '',
+			file_name TEXT NOT NULL DEFAULT '',
+			ptt INTEGER NOT NULL DEFAULT 0,
+			media BLOB,
+			message BLOB,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (chat, name)
+		)`,
+	)
+}
+
+func NewNote(ctx context.Context, r *ReplyMessage, name string) (*Note, error) {
+	note := &Note{Chat: r.Chat, Name: strings.ToLower(name), Type: MediaText}
+
+	full := r.Key().Message
+	if full == nil {
+		full = r.Message
+	}
+	inner := UnwrapMessage(full)
+	if inner == nil {
+		return nil, fmt.Errorf("message %s not found", r.ID)
+	}
+	note.Text = getMessageText(inner)
+	if info := getContextInfo(inner); info != nil {
+		for _, jid := range info.MentionedJID {
+			if parsed := ParseJID(jid); !parsed.IsEmpty() {
+				note.Mentions = append(note.Mentions, parsed)
+			}
+		}
+	}
+
+	var size uint64
+	switch {
+	case inner.GetConversation() != "" || inner.GetExtendedTextMessage() != nil:
+		return note, nil
+	case inner.GetImageMessage() != nil:
+		note.Type, note.Mimetype = MediaImage, inner.GetImageMessage().GetMimetype()
+		size = inner.GetImageMessage().GetFileLength()
+	case inner.GetVideoMessage() != nil:
+		note.Type, note.Mimetype = MediaVideo, inner.GetVideoMessage().GetMimetype()
+		size = inner.GetVideoMessage().GetFileLength()
+	case inner.GetAudioMessage() != nil:
+		note.Type, note.Mimetype = MediaAudio, inner.GetAudioMessage().GetMimetype()
+		note.PTT = inner.GetAudioMessage().GetPTT()
+		size = inner.GetAudioMessage().GetFileLength()
+	case inner.GetStickerMessage() != nil:
+		note.Type, note.Mimetype = MediaSticker, inner.GetStickerMessage().GetMimetype()
+		size = inner.GetStickerMessage().GetFileLength()
+	case inner.GetDocumentMessage() != nil:
+		note.Type, note.Mimetype = MediaDocument, inner.GetDocumentMessage().GetMimetype()
+		note.FileName = inner.GetDocumentMessage().GetFileName()
+		size = inner.GetDocumentMessage().GetFileLength()
+	default:
+		note.Type = MediaType(getContentType(inner))
+		note.Message = full
+		return note, nil
+	}
+
+	if size == 0 || size > noteMediaLimit {
+		note.Message = full
+		return note, nil
+	}
+
+	data, err := r.Client.DownloadAny(ctx, inner)
+	if err != nil {
+		return nil, err
+	}
+	note.Media = data
+	return note, nil
+}
+
+func SaveNote(n *Note) error {
+	var raw []byte
+	if n.Message != nil {
+		var err error
+		if raw, err = proto.Marshal(n.Message); err != nil {
+			return err
+		}
+	}
+
+	mentions := make([]string, len(n.Mentions))
+	for i, jid := range n.Mentions {
+		mentions[i] = jid.String()
+	}
+
+	_, err := DB.Exec(`INSERT OR REPLACE INTO notes (chat, name, type, text, mentions, mimetype, file_name, ptt, media, message, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		n.Chat.String(), strings.ToLower(n.Name), string(n.Type), n.Text, strings.Join(mentions, ","), n.Mimetype, n.FileName, n.PTT, n.Media, raw, time.Now().Unix())
+	return err
+}
+
+func GetNote(chat types.JID, name string) (*Note, error) {
+	n := &Note{Chat: chat}
+	var mediaType, mentions string
+	var raw []byte
+	var created int64
+	err := DB.QueryRow(`SELECT name, type, text, mentions, mimetype, file_name, ptt, media, message, created_at FROM notes WHERE chat = ? AND name = ?`,
+		chat.String(), strings.ToLower(name)).
+		Scan(&n.Name, &mediaType, &n.Text, &mentions, &n.Mimetype, &n.FileName, &n.PTT, &n.Media, &raw, &created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	n.Type = MediaType(mediaType)
+	n.CreatedAt = time.Unix(created, 0)
+	for _, jid := range strings.Split(mentions, ",") {
+		if parsed := ParseJID(jid); !parsed.IsEmpty() {
+			n.Mentions = append(n.Mentions, parsed)
+		}
+	}
+	if len(raw) > 0 {
+		n.Message = &waE2E.Message{}
+		if err := proto.Unmarshal(raw, n.Message); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func ListNotes(chat types.JID) ([]*Note, error) {
+	rows, err := DB.Query(`SELECT name, type, created_at FROM notes WHERE chat = ? ORDER BY name`, chat.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*Note
+	for rows.Next() {
+		n := &Note{Chat: chat}
+		var mediaType string
+		var created int64
+		if err := rows.Scan(&n.Name, &mediaType, &created); err != nil {
+			return nil, err
+		}
+		n.Type = MediaType(mediaType)
+		n.CreatedAt = time.Unix(created, 0)
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+func DeleteNote(chat types.JID, name string) (bool, error) {
+	res, err := DB.Exec(`DELETE FROM notes WHERE chat = ? AND name = ?`, chat.String(), strings.ToLower(name))
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (m *Message) SendNote(n *Note) (*Message, error) {
+	opts := SendOptions{Quoted: m, Mentions: n.Mentions}
+	switch {
+	case len(n.Media) > 0:
+		opts.Caption = n.Text
+		opts.Mimetype = n.Mimetype
+		opts.FileName = n.FileName
+		opts.PTT = n.PTT
+		return m.Send(n.Type, n.Media, opts)
+	case n.Type == MediaText:
+		return m.Send(MediaText, n.Text, opts)
+	default:
+		return m.Resend(n.Message, SendOptions{Quoted: m})
+	}
+}
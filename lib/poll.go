@@ -0,0 +1,247 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type Poll struct {
+	ID         string
+	Chat       types.JID
+	Sender     types.JID
+	Question   string
+	Options    []string
+	Selectable int
+	CreatedAt  time.Time
+}
+
+type PollOptionCount struct {
+	Name  string
+	Votes int
+}
+
+type PollUpdate struct {
+	Poll     *Poll
+	Voter    types.JID
+	Selected []string
+	Tally    []PollOptionCount
+}
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS polls (
+			id TEXT PRIMARY KEY,
+			chat TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			question TEXT NOT NULL,
+			options TEXT NOT NULL,
+			selectable INTEGER NOT NULL DEFAULT 1,
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS poll_votes (
+			poll_id TEXT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+			voter TEXT NOT NULL,
+			options TEXT NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (poll_id, voter)
+		)`,
+	)
+}
+
+func (m *Message) SendPoll(question string, options []string, selectableCount int) (*Message, error) {
+	if len(options) < 2 {
+		return nil, fmt.Errorf("a poll needs at least two options")
+	}
+	if option := duplicateOption(options); option != "" {
+		return nil, fmt.Errorf("option %q is listed more than once", option)
+	}
+
+	msg := m.Client.BuildPollCreation(question, options, selectableCount)
+	sent, err := m.deliver(msg, SendOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = SavePoll(&Poll{
+		ID:         sent.ID,
+		Chat:       m.Chat,
+		Sender:     m.Client.Store.ID.ToNonAD(),
+		Question:   question,
+		Options:    options,
+		Selectable: int(msg.PollCreationMessage.GetSelectableOptionsCount()),
+		CreatedAt:  time.Now(),
+	})
+	return sent, err
+}
+
+func SavePoll(p *Poll) error {
+	options, err := json.Marshal(p.Options)
+	if err != nil {
+		return err
+	}
+	_, err = DB.Exec(`INSERT OR REPLACE INTO polls (id, chat, sender, question, options, selectable, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, p.Chat.String(), p.Sender.String(), p.Question, string(options), p.Selectable, p.CreatedAt.Unix())
+	return err
+}
+
+func GetPoll(id string) (*Poll, error) {
+	var p Poll
+	var chat, sender, options string
+	var created int64
+	err := DB.QueryRow(`SELECT id, chat, sender, question, options, selectable, created_at FROM polls WHERE id = ?`, id).
+		Scan(&p.ID, &chat, &sender, &p.Question, &options, &p.Selectable, &created)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	p.Chat, _ = types.ParseJID(chat)
+	p.Sender, _ = types.ParseJID(sender)
+	p.CreatedAt = time.Unix(created, 0)
+	if err := json.Unmarshal([]byte(options), &p.Options); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func PollTally(p *Poll) ([]PollOptionCount, error) {
+	rows, err := DB.Query(`SELECT options FROM poll_votes WHERE poll_id = ?`, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var selected []string
+		if err := json.Unmarshal([]byte(raw), &selected); err != nil {
+			continue
+		}
+		for _, option := range selected {
+			counts[option]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tally := make([]PollOptionCount, len(p.Options))
+	for i, option := range p.Options {
+		tally[i] = PollOptionCount{Name: option, Votes: counts[option]}
+	}
+	return tally, nil
+}
+
+func HandlePoll(m *Message) error {
+	if m.Data == nil || m.Inner == nil {
+		return nil
+	}
+
+	if creation := getPollCreation(m.Inner); creation != nil {
+		options := make([]string, 0, len(creation.Options))
+		for _, option := range creation.Options {
+			options = append(options, option.GetOptionName())
+		}
+		if duplicateOption(options) != "" {
+			// Votes only carry option hashes, so repeated names can't be told apart.
+			return nil
+		}
+		return SavePoll(&Poll{
+			ID:         m.ID,
+			Chat:       m.Chat,
+			Sender:     m.Sender.ToNonAD(),
+			Question:   creation.GetName(),
+			Options:    options,
+			Selectable: int(creation.GetSelectableOptionsCount()),
+			CreatedAt:  m.Data.Info.Timestamp,
+		})
+	}
+
+	update := m.Inner.GetPollUpdateMessage()
+	if update == nil {
+		return nil
+	}
+
+	poll, err := GetPoll(update.GetPollCreationMessageKey().GetID())
+	if err != nil || poll == nil {
+		return err
+	}
+
+	vote, err := m.Client.DecryptPollVote(context.Background(), m.Data)
+	if err != nil {
+		return err
+	}
+
+	hashes := whatsmeow.HashPollOptions(poll.Options)
+	selected := []string{}
+	for _, hash := range vote.GetSelectedOptions() {
+		for i, optionHash := range hashes {
+			if bytes.Equal(hash, optionHash) {
+				selected = append(selected, poll.Options[i])
+				break
+			}
+		}
+	}
+
+	raw, err := json.Marshal(selected)
+	if err != nil {
+		return err
+	}
+	voter := m.Sender.ToNonAD()
+	_, err = DB.Exec(`INSERT OR REPLACE INTO poll_votes (poll_id, voter, options, updated_at) VALUES (?, ?, ?, ?)`,
+		poll.ID, voter.String(), string(raw), time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	tally, err := PollTally(poll)
+	if err != nil {
+		return err
+	}
+
+	m.PollUpdate = &PollUpdate{
+		Poll:     poll,
+		Voter:    voter,
+		Selected: selected,
+		Tally:    tally,
+	}
+	return nil
+}
+
+func duplicateOption(options []string) string {
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if seen[option] {
+			return option
+		}
+		seen[option] = true
+	}
+	return ""
+}
+
+func getPollCreation(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.PollCreationMessage != nil:
+		return msg.PollCreationMessage
+	case msg.PollCreationMessageV2 != nil:
+		return msg.PollCreationMessageV2
+	case msg.PollCreationMessageV3 != nil:
+		return msg.PollCreationMessageV3
+	case msg.PollCreationMessageV5 != nil:
+		return msg.PollCreationMessageV5
+	}
+	return nil
+}
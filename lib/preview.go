@@ -0,0 +1,338 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+type LinkPreview struct {
+	URL         string
+	Title       string
+	Description string
+	Image       []byte
+	Width       int
+	Height      int
+	Thumbnail   []byte
+
+	upload    *whatsmeow.UploadResponse
+	uploading bool
+}
+
+type previewEntry struct {
+	preview  *LinkPreview
+	err      error
+	expires  time.Time
+	lastUsed time.Time
+}
+
+type previewFetch struct {
+	done    chan struct{}
+	preview *LinkPreview
+	err     error
+}
+
+const (
+	previewCacheSize = 100
+	previewCacheTTL  = 30 * time.Minute
+	previewFailTTL   = 10 * time.Minute
+	previewTimeout   = 5 * time.Second
+	previewWait      = 2 * time.Second
+	previewImageSize = 600
+	previewMaxBody   = 512 * 1024
+	previewMaxImage  = 2 * 1024 * 1024
+)
+
+var (
+	urlRegex        = regexp.MustCompile(`https?://[^\s<>"]+`)
+	metaRegex       = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrRegex       = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*("([^"]*)"|'([^']*)')`)
+	titleRegex      = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	previewCache    = make(map[string]previewEntry)
+	previewInflight = make(map[string]*previewFetch)
+	previewMutex    sync.Mutex
+	previewHTTP     = &http.Client{
+		Timeout: previewTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: previewTimeout, Control: publicOnly}).DialContext,
+		},
+	}
+)
+
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("refusing to fetch preview from %s", host)
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+func FindURL(text string) string {
+	match := urlRegex.FindString(text)
+	return strings.TrimRight(match, ".,;:!?)]}'")
+}
+
+// GetLinkPreview returns the cached preview for link or waits for it to be
+// fetched until ctx is done. The fetch keeps running in the background when
+// ctx expires first, so a later call can pick up the result from the cache.
+func GetLinkPreview(ctx context.Context, link string) (*LinkPreview, error) {
+	previewMutex.Lock()
+	if entry, ok := previewCache[link]; ok && time.Now().Before(entry.expires) {
+		entry.lastUsed = time.Now()
+		previewCache[link] = entry
+		previewMutex.Unlock()
+		return entry.preview, entry.err
+	}
+	fetch, ok := previewInflight[link]
+	if !ok {
+		fetch = &previewFetch{done: make(chan struct{})}
+		previewInflight[link] = fetch
+		go loadLinkPreview(link, fetch)
+	}
+	previewMutex.Unlock()
+
+	select {
+	case <-fetch.done:
+		return fetch.preview, fetch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func loadLinkPreview(link string, fetch *previewFetch) {
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+
+	preview, err := fetchLinkPreview(ctx, link)
+	ttl := previewCacheTTL
+	if err != nil {
+		preview, ttl = nil, previewFailTTL
+	}
+
+	previewMutex.Lock()
+	evictPreviews()
+	now := time.Now()
+	previewCache[link] = previewEntry{preview: preview, err: err, expires: now.Add(ttl), lastUsed: now}
+	delete(previewInflight, link)
+	previewMutex.Unlock()
+
+	fetch.preview, fetch.err = preview, err
+	close(fetch.done)
+}
+
+// evictPreviews makes room for one more entry, dropping expired entries
+// first and then the least recently used ones. previewMutex must be held.
+func evictPreviews() {
+	if len(previewCache) < previewCacheSize {
+		return
+	}
+
+	now := time.Now()
+	for key, e := range previewCache {
+		if now.After(e.expires) {
+			delete(previewCache, key)
+		}
+	}
+
+	for len(previewCache) >= previewCacheSize {
+		var oldest string
+		var oldestUsed time.Time
+		for key, e := range previewCache {
+			if oldest == "" || e.lastUsed.Before(oldestUsed) {
+				oldest, oldestUsed = key, e.lastUsed
+			}
+		}
+		delete(previewCache, oldest)
+	}
+}
+
+func fetchLinkPreview(ctx context.Context, link string) (*LinkPreview, error) {
+	body, err := fetchLimited(ctx, link, previewMaxBody)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := parseMetaTags(string(body))
+	preview := &LinkPreview{
+		URL:         link,
+		Title:       firstNonEmpty(meta["og:title"], meta["twitter:title"]),
+		Description: firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+	}
+	if preview.Title == "" {
+		if match := titleRegex.FindStringSubmatch(string(body)); len(match) > 1 {
+			preview.Title = strings.TrimSpace(html.UnescapeString(match[1]))
+		}
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return nil, fmt.Errorf("no preview metadata found")
+	}
+
+	imageURL := firstNonEmpty(meta["og:image"], meta["og:image:url"], meta["twitter:image"])
+	if imageURL != "" {
+		if base, err := url.Parse(link); err == nil {
+			if ref, err := base.Parse(imageURL); err == nil {
+				imageURL = ref.String()
+			}
+		}
+		if data, err := fetchLimited(ctx, imageURL, previewMaxImage); err == nil {
+			if img, err := imaging.Decode(bytes.NewReader(data)); err == nil {
+				img = imaging.Fit(img, previewImageSize, previewImageSize, imaging.Lanczos)
+				bounds := img.Bounds()
+				buf := new(bytes.Buffer)
+				if imaging.Encode(buf, img, imaging.JPEG, imaging.JPEGQuality(80)) == nil {
+					preview.Image = buf.Bytes()
+					preview.Width = bounds.Dx()
+					preview.Height = bounds.Dy()
+				}
+				thumb := new(bytes.Buffer)
+				resized := imaging.Resize(img, 140, 0, imaging.Lanczos)
+				if imaging.Encode(thumb, resized, imaging.JPEG, imaging.JPEGQuality(60)) == nil {
+					preview.Thumbnail = thumb.Bytes()
+				}
+			}
+		}
+	}
+
+	return preview, nil
+}
+
+func fetchLimited(ctx context.Context, link string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "WhatsApp/2.23.20.0")
+
+	resp, err := previewHTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch preview: status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+func parseMetaTags(body string) map[string]string {
+	meta := make(map[string]string)
+	for _, tag := range metaRegex.FindAllString(body, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range attrRegex.FindAllStringSubmatch(tag, -1) {
+			value := attr[3]
+			if value == "" {
+				value = attr[4]
+			}
+			attrs[strings.ToLower(attr[1])] = value
+		}
+		key := firstNonEmpty(attrs["property"], attrs["name"])
+		if key == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		if _, exists := meta[key]; !exists {
+			meta[key] = strings.TrimSpace(html.UnescapeString(attrs["content"]))
+		}
+	}
+	return meta
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (m *Message) applyLinkPreview(msg *waE2E.ExtendedTextMessage) {
+	link := FindURL(msg.GetText())
+	if link == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), previewWait)
+	defer cancel()
+
+	preview, err := GetLinkPreview(ctx, link)
+	if err != nil {
+		return
+	}
+
+	msg.MatchedText = proto.String(link)
+	msg.Title = proto.String(preview.Title)
+	msg.Description = proto.String(preview.Description)
+	msg.PreviewType = waE2E.ExtendedTextMessage_NONE.Enum()
+
+	if preview.Thumbnail != nil {
+		msg.JPEGThumbnail = preview.Thumbnail
+		msg.PreviewType = waE2E.ExtendedTextMessage_IMAGE.Enum()
+	}
+
+	if preview.Image != nil {
+		if uploaded := m.previewUpload(preview); uploaded != nil {
+			msg.ThumbnailDirectPath = proto.String(uploaded.DirectPath)
+			msg.ThumbnailSHA256 = uploaded.FileSHA256
+			msg.ThumbnailEncSHA256 = uploaded.FileEncSHA256
+			msg.MediaKey = uploaded.MediaKey
+			msg.MediaKeyTimestamp = proto.Int64(time.Now().Unix())
+			msg.ThumbnailWidth = proto.Uint32(uint32(preview.Width))
+			msg.ThumbnailHeight = proto.Uint32(uint32(preview.Height))
+		}
+	}
+}
+
+// previewUpload returns the uploaded high quality thumbnail for preview. The
+// upload itself runs in the background, so the first message with a new link
+// goes out with the inline thumbnail only.
+func (m *Message) previewUpload(preview *LinkPreview) *whatsmeow.UploadResponse {
+	previewMutex.Lock()
+	defer previewMutex.Unlock()
+
+	if preview.upload == nil && !preview.uploading {
+		preview.uploading = true
+		go uploadPreviewImage(m.Client, preview)
+	}
+	return preview.upload
+}
+
+func uploadPreviewImage(client *whatsmeow.Client, preview *LinkPreview) {
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+
+	uploaded, err := client.Upload(ctx, preview.Image, whatsmeow.MediaLinkThumbnail)
+
+	previewMutex.Lock()
+	defer previewMutex.Unlock()
+	preview.uploading = false
+	if err == nil {
+		preview.upload = &uploaded
+	}
+}
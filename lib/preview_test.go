@@ -0,0 +1,130 @@
+package lib
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseMetaTags(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		key  string
+		want string
+	}{
+		{
+			name: "property before content",
+			body: `<meta property="og:title" content="Hello World">`,
+			key:  "og:title",
+			want: "Hello World",
+		},
+		{
+			name: "content before property",
+			body: `<meta content="Reversed" property="og:title" />`,
+			key:  "og:title",
+			want: "Reversed",
+		},
+		{
+			name: "single quotes and name attribute",
+			body: `<META NAME='Description' CONTENT='Some text'>`,
+			key:  "description",
+			want: "Some text",
+		},
+		{
+			name: "html entities",
+			body: `<meta property="og:description" content="Tom &amp; Jerry &#39;s">`,
+			key:  "og:description",
+			want: "Tom & Jerry 's",
+		},
+		{
+			name: "first tag wins",
+			body: `<meta property="og:image" content="first.png"><meta property="og:image" content="second.png">`,
+			key:  "og:image",
+			want: "first.png",
+		},
+		{
+			name: "multiline tag",
+			body: "<meta\n  property=\"twitter:title\"\n  content=\"Split\"\n>",
+			key:  "twitter:title",
+			want: "Split",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMetaTags(tt.body)[tt.key]; got != tt.want {
+				t.Errorf("parseMetaTags()[%q] = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindURL(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"see https://example.com/a?b=c.", "https://example.com/a?b=c"},
+		{"(http://example.org)", "http://example.org"},
+		{"no link here", ""},
+		{"example.com without scheme", ""},
+	}
+
+	for _, tt := range tests {
+		if got := FindURL(tt.text); got != tt.want {
+			t.Errorf("FindURL(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.5", false},
+		{"192.168.1.1", false},
+		{"172.16.0.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestEvictPreviews(t *testing.T) {
+	previewMutex.Lock()
+	defer previewMutex.Unlock()
+	saved := previewCache
+	defer func() { previewCache = saved }()
+
+	now := time.Now()
+	previewCache = make(map[string]previewEntry)
+	for i := 0; i < previewCacheSize; i++ {
+		previewCache[fmt.Sprint(i)] = previewEntry{expires: now.Add(time.Hour), lastUsed: now.Add(time.Duration(i) * time.Second)}
+	}
+	previewCache["0"] = previewEntry{expires: now.Add(time.Hour), lastUsed: now.Add(time.Hour)}
+
+	evictPreviews()
+	if _, ok := previewCache["1"]; ok || len(previewCache) != previewCacheSize-1 {
+		t.Errorf("expected only the least recently used entry to be evicted")
+	}
+
+	previewCache["expired"] = previewEntry{expires: now.Add(-time.Minute), lastUsed: now.Add(time.Hour)}
+	evictPreviews()
+	if _, ok := previewCache["expired"]; ok || len(previewCache) != previewCacheSize-1 {
+		t.Errorf("expected the expired entry to be evicted first")
+	}
+}
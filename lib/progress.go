@@ -0,0 +1,115 @@
+package lib
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const progressInterval = 2 * time.Second
+
+type Progress struct {
+	origin   *Message
+	message  *Message
+	mu       sync.Mutex
+	text     string
+	lastEdit time.Time
+	finished bool
+}
+
+func (m *Message) Progress(text string) *Progress {
+	p := &Progress{origin: m, text: text}
+	sent, err := m.Reply(text)
+	if err == nil {
+		p.message = sent
+		p.lastEdit = time.Now()
+	}
+	return p
+}
+
+func (p *Progress) Update(text string) {
+	p.update(text, true)
+}
+
+func (p *Progress) Percent(label string, percent int) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	p.update(fmt.Sprintf("_%s %d%%_", label, percent), percent == 100)
+}
+
+func (p *Progress) update(text string, force bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished || p.message == nil || text == p.text {
+		return
+	}
+	if !force && time.Since(p.lastEdit) < progressInterval {
+		return
+	}
+
+	if _, err := p.message.Edit(text); err == nil {
+		p.text = text
+		p.lastEdit = time.Now()
+	}
+}
+
+func (p *Progress) Done(text string) {
+	p.finish(text)
+}
+
+func (p *Progress) Fail(text string) {
+	if text == "" {
+		text = "_Something went wrong_"
+	}
+	p.finish(text)
+	p.origin.markFailed()
+}
+
+func (p *Progress) finish(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+	p.finished = true
+
+	if p.message == nil {
+		if text != "" {
+			p.origin.Reply(text)
+		}
+		return
+	}
+
+	if text == "" {
+		p.message.Delete()
+		return
+	}
+	p.message.Edit(text)
+}
+
+func (p *Progress) Reader(r io.Reader, total int64, label string) io.Reader {
+	return &progressReader{reader: r, total: total, label: label, progress: p}
+}
+
+type progressReader struct {
+	reader   io.Reader
+	total    int64
+	read     int64
+	label    string
+	progress *Progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	r.read += int64(n)
+	if r.total > 0 {
+		r.progress.Percent(r.label, int(r.read*100/r.total))
+	}
+	return n, err
+}
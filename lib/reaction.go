@@ -0,0 +1,81 @@
+package lib
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type Reaction struct {
+	Key     MessageKey
+	Emoji   string
+	Removed bool
+}
+
+func (m *Message) React(emoji string) error {
+	m.reaction = emoji
+	return react(m.Client, m.Key(), emoji)
+}
+
+// LastReaction returns the last emoji the bot reacted to this message with.
+func (m *Message) LastReaction() string {
+	return m.reaction
+}
+
+// Fail replies with text and, if the command is still marked as running,
+// replaces the ⏳ reaction with ❌ so it does not get marked as done.
+func (m *Message) Fail(text string) {
+	if text != "" {
+		m.Reply(text)
+	}
+	m.markFailed()
+}
+
+func (m *Message) markFailed() {
+	if m.reaction == "⏳" {
+		m.React("❌")
+	}
+}
+
+func (r *ReplyMessage) React(emoji string) error {
+	return react(r.Client, r.Key(), emoji)
+}
+
+func react(client *whatsmeow.Client, key MessageKey, emoji string) error {
+	sender := key.Participant
+	if sender.IsEmpty() && key.FromMe {
+		sender = client.Store.ID.ToNonAD()
+	}
+	_, err := client.SendMessage(context.Background(), key.Chat, client.BuildReaction(key.Chat, sender, key.ID, emoji))
+	return err
+}
+
+func parseReaction(client *whatsmeow.Client, info types.MessageInfo, msg *waE2E.ReactionMessage) *Reaction {
+	if msg == nil || msg.Key == nil {
+		return nil
+	}
+
+	key := MessageKey{
+		Chat: info.Chat,
+		ID:   msg.Key.GetID(),
+	}
+	switch {
+	case msg.Key.GetFromMe():
+		key.Participant = info.Sender.ToNonAD()
+	case info.IsGroup:
+		key.Participant = ParseJID(msg.Key.GetParticipant())
+	case info.IsFromMe:
+		key.Participant = info.Chat.ToNonAD()
+	case client.Store.ID != nil:
+		key.Participant = client.Store.ID.ToNonAD()
+	}
+	key.FromMe = IsOwnJID(client, key.Participant)
+
+	return &Reaction{
+		Key:     key,
+		Emoji:   msg.GetText(),
+		Removed: msg.GetText() == "",
+	}
+}
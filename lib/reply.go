@@ -12,32 +12,37 @@ import (
 )
 
 type ReplyMessage struct {
-	Client   *whatsmeow.Client
-	ID       string
-	Sender   types.JID
-	FromMe   bool
-	Chat     types.JID
-	Type     string
-	Text     string
-	IsGroup  bool
-	IsPm     bool
-	IsBot    bool
-	IsSudo   bool
-	Message  *waE2E.Message
+	Client       *whatsmeow.Client
+	ID           string
+	Sender       types.JID
+	SenderPN     types.JID
+	FromMe       bool
+	Chat         types.JID
+	Type         string
+	Text         string
+	IsGroup      bool
+	IsPm         bool
+	IsBot        bool
+	IsSudo       bool
+	Message      *waE2E.Message
+	Inner        *waE2E.Message
+	MentionedJid []types.JID
+	IsViewOnce   bool
 }
 
 func NewReplyMessage(client *whatsmeow.Client, evt *events.Message) *ReplyMessage {
-	if evt.Message.ExtendedTextMessage == nil || evt.Message.ExtendedTextMessage.ContextInfo == nil {
+	ctx := getContextInfo(evt.Message)
+	if ctx == nil || ctx.QuotedMessage == nil || ctx.StanzaID == nil {
 		return nil
 	}
 
-	ctx := evt.Message.ExtendedTextMessage.ContextInfo
 	quotedMsg := ctx.QuotedMessage
 
 	var sender types.JID
 	if ctx.Participant != nil {
-		sender = types.NewJID(strings.Split(*ctx.Participant, "@")[0], types.DefaultUserServer)
-	} else {
+		sender, _ = types.ParseJID(*ctx.Participant)
+	}
+	if sender.IsEmpty() {
 		sender = evt.Info.Sender
 	}
 
@@ -45,33 +50,34 @@ func NewReplyMessage(client *whatsmeow.Client, evt *events.Message) *ReplyMessag
 		Client:  client,
 		ID:      *ctx.StanzaID,
 		Sender:  sender,
-		FromMe:  sender.User == client.Store.ID.User,
+		FromMe:  IsOwnJID(client, sender),
 		Chat:    evt.Info.Chat,
 		IsGroup: evt.Info.IsGroup,
 		IsPm:    !evt.Info.IsGroup,
 		Message: quotedMsg,
 	}
 
-	reply.Type = getContentType(quotedMsg)
-	reply.Text = getMessageText(quotedMsg)
-	reply.IsBot = strings.HasPrefix(reply.ID, "BAE5") && len(reply.ID) == 16
-
-	sudos := strings.Split(Config.SUDO, ",")
-	sudoJids := []string{client.Store.ID.User}
-	sudoJids = append(sudoJids, sudos...)
-	
-	for _, sudo := range sudoJids {
-		if sudo != "" && sudo+"@s.whatsapp.net" == reply.Sender.String() {
-			reply.IsSudo = true
-			break
+	reply.Inner = UnwrapMessage(quotedMsg)
+	reply.Type = getContentType(reply.Inner)
+	reply.Text = getMessageText(reply.Inner)
+	reply.IsViewOnce = isViewOnce(quotedMsg)
+	if quotedCtx := getContextInfo(reply.Inner); quotedCtx != nil {
+		for _, jid := range quotedCtx.MentionedJID {
+			if parsed := ParseJID(jid); !parsed.IsEmpty() {
+				reply.MentionedJid = append(reply.MentionedJid, parsed)
+			}
 		}
 	}
+	reply.IsBot = strings.HasPrefix(reply.ID, "BAE5") && len(reply.ID) == 16
+
+	reply.SenderPN = ToPN(client, reply.Sender)
+	reply.IsSudo = IsSudo(client, reply.SenderPN)
 
 	return reply
 }
 
 func (r *ReplyMessage) Reply(text string) (*Message, error) {
-	response, err := r.Client.SendMessage(context.Background(), r.Chat, &waE2E.Message{
+	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
 			Text: proto.String(text),
 			ContextInfo: &waE2E.ContextInfo{
@@ -80,10 +86,12 @@ func (r *ReplyMessage) Reply(text string) (*Message, error) {
 				QuotedMessage: r.Message,
 			},
 		},
-	})
+	}
+	response, err := r.Client.SendMessage(context.Background(), r.Chat, msg)
 	if err != nil {
 		return nil, err
 	}
+	StoreSent(r.Chat, response.ID, msg, response.Timestamp)
 
 	return &Message{
 		Client: r.Client,
@@ -93,7 +101,28 @@ func (r *ReplyMessage) Reply(text string) (*Message, error) {
 	}, nil
 }
 
+func (r *ReplyMessage) Key() MessageKey {
+	key := MessageKey{
+		Chat:        r.Chat,
+		ID:          r.ID,
+		Participant: r.Sender,
+		FromMe:      r.FromMe,
+		Message:     r.Message,
+	}
+	if full, err := r.Full(); err == nil && full != nil {
+		key.Participant = full.Info.Sender.ToNonAD()
+		key.FromMe = full.Info.IsFromMe
+		key.Message = full.Message
+	}
+	return key
+}
+
 func (r *ReplyMessage) Delete() error {
-	_, err := r.Client.SendMessage(context.Background(), r.Chat, r.Client.BuildRevoke(r.Chat, types.EmptyJID, r.ID))
+	key := r.Key()
+	sender := types.EmptyJID
+	if !key.FromMe {
+		sender = key.Participant
+	}
+	_, err := r.Client.SendMessage(context.Background(), r.Chat, r.Client.BuildRevoke(r.Chat, sender, r.ID))
 	return err
 }
@@ -10,11 +10,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/disintegration/imaging"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -27,19 +29,29 @@ const (
 	MediaAudio    MediaType = "audio"
 	MediaSticker  MediaType = "sticker"
 	MediaDocument MediaType = "document"
+	MediaLocation MediaType = "location"
+	MediaLive     MediaType = "livelocation"
+	MediaContact  MediaType = "contact"
 )
 
 type SendOptions struct {
-	Caption  string
-	FileName string
-	Mimetype string
-	Quoted   bool
+	Caption             string
+	FileName            string
+	Mimetype            string
+	Quoted              interface{}
+	PTT                 bool
+	Mentions            []types.JID
+	Forwarded           bool
+	FrequentlyForwarded bool
+	Ephemeral           uint32
+	ViewOnce            bool
+	NoLinkPreview       bool
 }
 
+var mentionRegex = regexp.MustCompile(`@(\d{5,16})`)
+
 func (m *Message) Send(mediaType interface{}, content ...interface{}) (*Message, error) {
 	var opts SendOptions
-	var data []byte
-	var err error
 
 	if len(content) == 0 {
 		return nil, fmt.Errorf("no content provided")
@@ -73,31 +85,46 @@ func (m *Message) Send(mediaType interface{}, content ...interface{}) (*Message,
 			return nil, fmt.Errorf("invalid text content type")
 		}
 
-		if opts.Quoted && m.Data != nil {
-			return m.Reply(text)
-		}
-		return m.sendText(text)
+		text, opts.Mentions = m.addressMentions(text, opts.Mentions)
+		return m.sendText(text, opts)
 	}
 
-	switch v := contentData.(type) {
-	case string:
-		if isURL(v) {
-			data, err = getBuffer(v)
-		} else {
-			data, err = os.ReadFile(v)
-			if opts.FileName == "" {
-				opts.FileName = filepath.Base(v)
-			}
+	switch mType {
+	case MediaLocation:
+		switch v := contentData.(type) {
+		case Location:
+			return m.sendLocation(v, opts)
+		case *Location:
+			return m.sendLocation(*v, opts)
 		}
-	case []byte:
-		data = v
-	default:
-		return nil, fmt.Errorf("unsupported content type")
+		return nil, fmt.Errorf("invalid location content type")
+	case MediaLive:
+		switch v := contentData.(type) {
+		case LiveLocation:
+			return m.sendLiveLocation(v, opts)
+		case *LiveLocation:
+			return m.sendLiveLocation(*v, opts)
+		}
+		return nil, fmt.Errorf("invalid live location content type")
+	case MediaContact:
+		switch v := contentData.(type) {
+		case Contact:
+			return m.sendContacts([]Contact{v}, opts)
+		case []Contact:
+			return m.sendContacts(v, opts)
+		}
+		return nil, fmt.Errorf("invalid contact content type")
 	}
 
+	opts.Caption, opts.Mentions = m.addressMentions(opts.Caption, opts.Mentions)
+
+	data, fileName, err := loadMedia(contentData)
 	if err != nil {
 		return nil, err
 	}
+	if opts.FileName == "" {
+		opts.FileName = fileName
+	}
 
 	if opts.Mimetype == "" {
 		opts.Mimetype = detectMimetype(mType, data)
@@ -119,23 +146,40 @@ func (m *Message) Send(mediaType interface{}, content ...interface{}) (*Message,
 	}
 }
 
-func (m *Message) sendText(text string) (*Message, error) {
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, &waE2E.Message{
-		Conversation: proto.String(text),
-	})
-	if err != nil {
-		return nil, err
+func (m *Message) sendText(text string, opts SendOptions) (*Message, error) {
+	contextInfo := m.contextInfo(text, opts)
+	hasLink := FindURL(text) != ""
+	if contextInfo == nil && !hasLink {
+		return m.deliver(&waE2E.Message{
+			Conversation: proto.String(text),
+		}, opts)
 	}
 
-	return &Message{
-		Client: m.Client,
-		ID:     response.ID,
-		Chat:   m.Chat,
-		FromMe: true,
-	}, nil
+	msg := &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(text),
+			ContextInfo: contextInfo,
+		},
+	}
+
+	if opts.NoLinkPreview {
+		msg.ExtendedTextMessage.PreviewType = waE2E.ExtendedTextMessage_NONE.Enum()
+	} else if hasLink {
+		m.applyLinkPreview(msg.ExtendedTextMessage)
+	}
+
+	return m.deliver(msg, opts)
 }
 
 func (m *Message) sendImage(data []byte, opts SendOptions) (*Message, error) {
+	msg, err := m.buildImage(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.deliver(msg, opts)
+}
+
+func (m *Message) buildImage(data []byte, opts SendOptions) (*waE2E.Message, error) {
 	uploaded, err := m.Client.Upload(context.Background(), data, whatsmeow.MediaImage)
 	if err != nil {
 		return nil, err
@@ -184,28 +228,24 @@ func (m *Message) sendImage(data []byte, opts SendOptions) (*Message, error) {
 		msg.ImageMessage.Caption = proto.String(opts.Caption)
 	}
 
-	if opts.Quoted && m.Data != nil {
-		msg.ImageMessage.ContextInfo = &waE2E.ContextInfo{
-			StanzaID:      proto.String(m.ID),
-			Participant:   proto.String(m.Sender.String()),
-			QuotedMessage: m.Data.Message,
-		}
+	msg.ImageMessage.ContextInfo = m.contextInfo(opts.Caption, opts)
+
+	if opts.ViewOnce {
+		msg.ImageMessage.ViewOnce = proto.Bool(true)
 	}
 
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, msg)
+	return msg, nil
+}
+
+func (m *Message) sendVideo(data []byte, opts SendOptions) (*Message, error) {
+	msg, err := m.buildVideo(data, opts)
 	if err != nil {
 		return nil, err
 	}
-
-	return &Message{
-		Client: m.Client,
-		ID:     response.ID,
-		Chat:   m.Chat,
-		FromMe: true,
-	}, nil
+	return m.deliver(msg, opts)
 }
 
-func (m *Message) sendVideo(data []byte, opts SendOptions) (*Message, error) {
+func (m *Message) buildVideo(data []byte, opts SendOptions) (*waE2E.Message, error) {
 	uploaded, err := m.Client.Upload(context.Background(), data, whatsmeow.MediaVideo)
 	if err != nil {
 		return nil, err
@@ -250,28 +290,36 @@ func (m *Message) sendVideo(data []byte, opts SendOptions) (*Message, error) {
 		msg.VideoMessage.Caption = proto.String(opts.Caption)
 	}
 
-	if opts.Quoted && m.Data != nil {
-		msg.VideoMessage.ContextInfo = &waE2E.ContextInfo{
-			StanzaID:      proto.String(m.ID),
-			Participant:   proto.String(m.Sender.String()),
-			QuotedMessage: m.Data.Message,
-		}
-	}
+	msg.VideoMessage.ContextInfo = m.contextInfo(opts.Caption, opts)
 
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, msg)
-	if err != nil {
-		return nil, err
+	if opts.ViewOnce {
+		msg.VideoMessage.ViewOnce = proto.Bool(true)
 	}
 
-	return &Message{
-		Client: m.Client,
-		ID:     response.ID,
-		Chat:   m.Chat,
-		FromMe: true,
-	}, nil
+	return msg, nil
 }
 
 func (m *Message) sendAudio(data []byte, opts SendOptions) (*Message, error) {
+	var seconds uint32
+	var waveform []byte
+
+	if opts.PTT && HasFFmpeg() {
+		if converted, err := ToOpus(context.Background(), data); err == nil {
+			data = converted
+		}
+	}
+	if opts.PTT && !isOpus(data) {
+		opts.PTT = false
+	}
+	if opts.PTT {
+		opts.Mimetype = "audio/ogg; codecs=opus"
+		if HasFFmpeg() {
+			seconds, waveform, _ = AudioWaveform(context.Background(), data)
+		}
+	} else if strings.Contains(opts.Mimetype, "opus") && !isOpus(data) {
+		opts.Mimetype = detectMimetype(MediaAudio, data)
+	}
+
 	uploaded, err := m.Client.Upload(context.Background(), data, whatsmeow.MediaAudio)
 	if err != nil {
 		return nil, err
@@ -289,25 +337,23 @@ func (m *Message) sendAudio(data []byte, opts SendOptions) (*Message, error) {
 		},
 	}
 
-	if opts.Quoted && m.Data != nil {
-		msg.AudioMessage.ContextInfo = &waE2E.ContextInfo{
-			StanzaID:      proto.String(m.ID),
-			Participant:   proto.String(m.Sender.String()),
-			QuotedMessage: m.Data.Message,
+	if opts.PTT {
+		msg.AudioMessage.PTT = proto.Bool(true)
+		if seconds > 0 {
+			msg.AudioMessage.Seconds = proto.Uint32(seconds)
+		}
+		if waveform != nil {
+			msg.AudioMessage.Waveform = waveform
 		}
 	}
 
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, msg)
-	if err != nil {
-		return nil, err
+	msg.AudioMessage.ContextInfo = m.contextInfo("", opts)
+
+	if opts.ViewOnce {
+		msg.AudioMessage.ViewOnce = proto.Bool(true)
 	}
 
-	return &Message{
-		Client: m.Client,
-		ID:     response.ID,
-		Chat:   m.Chat,
-		FromMe: true,
-	}, nil
+	return m.deliver(msg, opts)
 }
 
 func (m *Message) sendSticker(data []byte, opts SendOptions) (*Message, error) {
@@ -328,25 +374,9 @@ func (m *Message) sendSticker(data []byte, opts SendOptions) (*Message, error) {
 		},
 	}
 
-	if opts.Quoted && m.Data != nil {
-		msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{
-			StanzaID:      proto.String(m.ID),
-			Participant:   proto.String(m.Sender.String()),
-			QuotedMessage: m.Data.Message,
-		}
-	}
+	msg.StickerMessage.ContextInfo = m.contextInfo("", opts)
 
-	response, err := m.Client.SendMessage(context.Background(), m.Chat, msg)
-	if err != nil {
-		return nil, err
-	}
-
-	return &Message{
-		Client: m.Client,
-		ID:     response.ID,
-		Chat:   m.Chat,
-		FromMe: true,
-	}, nil
+	return m.deliver(msg, opts)
 }
 
 func (m *Message) sendDocument(data []byte, opts SendOptions) (*Message, error) {
@@ -376,11 +406,197 @@ func (m *Message) sendDocument(data []byte, opts SendOptions) (*Message, error)
 		msg.DocumentMessage.Caption = proto.String(opts.Caption)
 	}
 
-	if opts.Quoted && m.Data != nil {
-		msg.DocumentMessage.ContextInfo = &waE2E.ContextInfo{
-			StanzaID:      proto.String(m.ID),
-			Participant:   proto.String(m.Sender.String()),
-			QuotedMessage: m.Data.Message,
+	msg.DocumentMessage.ContextInfo = m.contextInfo(opts.Caption, opts)
+
+	return m.deliver(msg, opts)
+}
+
+func (m *Message) contextInfo(text string, opts SendOptions) *waE2E.ContextInfo {
+	info := &waE2E.ContextInfo{}
+	used := false
+
+	if key, ok := m.quotedKey(opts.Quoted); ok {
+		info.StanzaID = proto.String(key.ID)
+		info.QuotedMessage = key.Message
+		if info.QuotedMessage == nil {
+			info.QuotedMessage = &waE2E.Message{Conversation: proto.String("")}
+		}
+		if !key.Participant.IsEmpty() {
+			info.Participant = proto.String(key.Participant.ToNonAD().String())
+		}
+		if !key.Chat.IsEmpty() && key.Chat != m.Chat {
+			info.RemoteJID = proto.String(key.Chat.String())
+		}
+		used = true
+	}
+
+	if mentions := m.mentionedJIDs(text, opts.Mentions); len(mentions) > 0 {
+		info.MentionedJID = mentions
+		used = true
+	}
+
+	if opts.FrequentlyForwarded {
+		info.IsForwarded = proto.Bool(true)
+		info.ForwardingScore = proto.Uint32(127)
+		used = true
+	} else if opts.Forwarded {
+		info.IsForwarded = proto.Bool(true)
+		info.ForwardingScore = proto.Uint32(1)
+		used = true
+	}
+
+	expiration := opts.Ephemeral
+	if expiration == 0 {
+		expiration = m.chatExpiration()
+	}
+	if expiration > 0 {
+		info.Expiration = proto.Uint32(expiration)
+		used = true
+	}
+
+	if !used {
+		return nil
+	}
+	return info
+}
+
+func (m *Message) quotedKey(quoted interface{}) (MessageKey, bool) {
+	var key MessageKey
+	switch q := quoted.(type) {
+	case bool:
+		if !q || m.Data == nil {
+			return key, false
+		}
+		key = m.Key()
+	case *Message:
+		if q == nil {
+			return key, false
+		}
+		key = q.Key()
+	case *ReplyMessage:
+		if q == nil {
+			return key, false
+		}
+		key = q.Key()
+	case MessageKey:
+		key = q
+	case *MessageKey:
+		if q == nil {
+			return key, false
+		}
+		key = *q
+	default:
+		return key, false
+	}
+
+	if key.ID == "" {
+		return key, false
+	}
+	if key.Chat.IsEmpty() {
+		key.Chat = m.Chat
+	}
+	if key.Participant.IsEmpty() && key.FromMe && m.Client.Store.ID != nil {
+		key.Participant = m.Client.Store.ID.ToNonAD()
+	}
+	if key.Participant.IsEmpty() && key.Chat.Server != types.GroupServer {
+		key.Participant = key.Chat
+	}
+	if key.Participant.IsEmpty() {
+		stored, err := LoadMessage(key.Chat, key.ID)
+		if err != nil || stored == nil {
+			return key, false
+		}
+		key.Participant = stored.Info.Sender.ToNonAD()
+		if key.Message == nil {
+			key.Message = stored.Message
+		}
+	}
+	return key, true
+}
+
+func (m *Message) chatExpiration() uint32 {
+	if m.Data == nil || m.Data.Message == nil {
+		return 0
+	}
+	if ctx := getContextInfo(m.Data.Message); ctx != nil {
+		return ctx.GetExpiration()
+	}
+	return 0
+}
+
+func (m *Message) mentionedJIDs(text string, mentions []types.JID) []string {
+	seen := make(map[string]bool)
+	covered := make(map[string]bool)
+	var jids []string
+
+	add := func(jid types.JID) {
+		covered[jid.User] = true
+		jid = m.mentionAddress(jid)
+		covered[jid.User] = true
+		if !seen[jid.String()] {
+			seen[jid.String()] = true
+			jids = append(jids, jid.String())
+		}
+	}
+
+	for _, jid := range mentions {
+		add(jid)
+	}
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		if !covered[match[1]] {
+			add(types.NewJID(match[1], types.DefaultUserServer))
+		}
+	}
+
+	return jids
+}
+
+func (m *Message) addressMentions(text string, mentions []types.JID) (string, []types.JID) {
+	var resolved []types.JID
+	rewrite := make(map[string]string)
+
+	for _, jid := range mentions {
+		target := m.mentionAddress(jid)
+		if target.User != jid.User {
+			rewrite[jid.User] = target.User
+		}
+		rewrite[target.User] = target.User
+		resolved = append(resolved, target)
+	}
+
+	text = mentionRegex.ReplaceAllStringFunc(text, func(match string) string {
+		user := match[1:]
+		if target, ok := rewrite[user]; ok {
+			return "@" + target
+		}
+		target := m.mentionAddress(types.NewJID(user, types.DefaultUserServer))
+		rewrite[user] = target.User
+		rewrite[target.User] = target.User
+		resolved = append(resolved, target)
+		return "@" + target.User
+	})
+
+	return text, resolved
+}
+
+func (m *Message) mentionAddress(jid types.JID) types.JID {
+	if m.Data == nil && m.Chat.Server == types.GroupServer {
+		if info, err := GetGroupInfo(m.Client, m.Chat); err == nil {
+			return ParticipantJID(m.Client, info, jid)
+		}
+	}
+	if m.Data != nil && m.Data.Info.AddressingMode == types.AddressingModeLID {
+		return ToLID(m.Client, jid)
+	}
+	return ToPN(m.Client, jid)
+}
+
+func (m *Message) deliver(msg *waE2E.Message, opts SendOptions) (*Message, error) {
+	if opts.ViewOnce && (msg.ImageMessage != nil || msg.VideoMessage != nil || msg.AudioMessage != nil) {
+		msg = &waE2E.Message{
+			ViewOnceMessageV2: &waE2E.FutureProofMessage{
+				Message: msg,
+			},
 		}
 	}
 
@@ -388,6 +604,7 @@ func (m *Message) sendDocument(data []byte, opts SendOptions) (*Message, error)
 	if err != nil {
 		return nil, err
 	}
+	StoreSent(m.Chat, response.ID, msg, response.Timestamp)
 
 	return &Message{
 		Client: m.Client,
@@ -397,6 +614,22 @@ func (m *Message) sendDocument(data []byte, opts SendOptions) (*Message, error)
 	}, nil
 }
 
+func loadMedia(content interface{}) ([]byte, string, error) {
+	switch v := content.(type) {
+	case string:
+		if isURL(v) {
+			data, err := getBuffer(v)
+			return data, "", err
+		}
+		data, err := os.ReadFile(v)
+		return data, filepath.Base(v), err
+	case []byte:
+		return v, "", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported content type")
+	}
+}
+
 func isURL(str string) bool {
 	return strings.HasPrefix(str, "http://") || strings.HasPrefix(str, "https://")
 }
@@ -422,7 +655,13 @@ func detectMimetype(mediaType MediaType, data []byte) string {
 	case MediaVideo:
 		return "video/mp4"
 	case MediaAudio:
-		return "audio/ogg; codecs=opus"
+		if isOpus(data) {
+			return "audio/ogg; codecs=opus"
+		}
+		if mimetype := http.DetectContentType(data); strings.HasPrefix(mimetype, "audio/") {
+			return mimetype
+		}
+		return "audio/mpeg"
 	case MediaSticker:
 		return "image/webp"
 	case MediaDocument:
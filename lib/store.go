@@ -0,0 +1,142 @@
+package lib
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+const pruneEvery = 500
+
+var storeWrites atomic.Int64
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS messages (
+			chat TEXT NOT NULL,
+			id TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			sender_alt TEXT NOT NULL DEFAULT '',
+			from_me INTEGER NOT NULL DEFAULT 0,
+			is_group INTEGER NOT NULL DEFAULT 0,
+			push_name TEXT NOT NULL DEFAULT '',
+			timestamp INTEGER NOT NULL,
+			message BLOB NOT NULL,
+			PRIMARY KEY (chat, id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS messages_timestamp ON messages (timestamp)`,
+	)
+}
+
+func StoreMessage(evt *events.Message) error {
+	if DB == nil || evt == nil || evt.Message == nil || evt.Info.ID == "" {
+		return nil
+	}
+	if evt.Message.GetProtocolMessage() != nil || evt.Message.GetReactionMessage() != nil || evt.Message.GetPollUpdateMessage() != nil {
+		return nil
+	}
+	return saveMessage(evt.Info, evt.Message)
+}
+
+func StoreSent(chat types.JID, id string, msg *waE2E.Message, timestamp time.Time) error {
+	if DB == nil || Client == nil || Client.Store.ID == nil || msg == nil || msg.GetProtocolMessage() != nil || msg.GetEditedMessage() != nil {
+		return nil
+	}
+	return saveMessage(types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     chat,
+			Sender:   Client.Store.ID.ToNonAD(),
+			IsFromMe: true,
+			IsGroup:  chat.Server == types.GroupServer,
+		},
+		ID:        id,
+		Timestamp: timestamp,
+	}, msg)
+}
+
+func saveMessage(info types.MessageInfo, msg *waE2E.Message) error {
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`INSERT OR REPLACE INTO messages (chat, id, sender, sender_alt, from_me, is_group, push_name, timestamp, message) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		info.Chat.String(), info.ID, info.Sender.String(), info.SenderAlt.String(), info.IsFromMe, info.IsGroup, info.PushName, info.Timestamp.Unix(), raw)
+	if err != nil {
+		return err
+	}
+
+	if storeWrites.Add(1)%pruneEvery == 0 {
+		go PruneMessages()
+	}
+	return nil
+}
+
+func LoadMessage(chat types.JID, id string) (*events.Message, error) {
+	if DB == nil {
+		return nil, nil
+	}
+
+	var sender, senderAlt, pushName string
+	var fromMe, isGroup bool
+	var timestamp int64
+	var raw []byte
+	err := DB.QueryRow(`SELECT sender, sender_alt, from_me, is_group, push_name, timestamp, message FROM messages WHERE chat = ? AND id = ?`, chat.String(), id).
+		Scan(&sender, &senderAlt, &fromMe, &isGroup, &pushName, &timestamp, &raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var msg waE2E.Message
+	if err := proto.Unmarshal(raw, &msg); err != nil {
+		return nil, err
+	}
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     chat,
+				IsFromMe: fromMe,
+				IsGroup:  isGroup,
+			},
+			ID:        id,
+			PushName:  pushName,
+			Timestamp: time.Unix(timestamp, 0),
+		},
+		Message:    &msg,
+		RawMessage: &msg,
+	}
+	evt.Info.Sender, _ = types.ParseJID(sender)
+	evt.Info.SenderAlt, _ = types.ParseJID(senderAlt)
+	return evt, nil
+}
+
+func PruneMessages() error {
+	if DB == nil {
+		return nil
+	}
+	cutoff := time.Now().Add(-time.Duration(Config.MSG_STORE_HOURS) * time.Hour)
+	if _, err := DB.Exec(`DELETE FROM messages WHERE timestamp < ?`, cutoff.Unix()); err != nil {
+		return err
+	}
+	if Config.MSG_STORE_MAX <= 0 {
+		return nil
+	}
+	_, err := DB.Exec(`DELETE FROM messages WHERE rowid IN (
+		SELECT rowid FROM (
+			SELECT rowid, ROW_NUMBER() OVER (PARTITION BY chat ORDER BY timestamp DESC) AS n FROM messages
+		) WHERE n > ?
+	)`, Config.MSG_STORE_MAX)
+	return err
+}
+
+func (r *ReplyMessage) Full() (*events.Message, error) {
+	return LoadMessage(r.Chat, r.ID)
+}
@@ -56,6 +56,18 @@ func getEnvBool(key string, defaultVal bool) bool {
 	return b
 }
 
+func getEnvInt(key string, defaultVal int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return defaultVal
+	}
+	return i
+}
+
 func Mode() bool {
 	return Config.MODE != "public"
 }
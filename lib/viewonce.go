@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func isViewOnce(msg *waE2E.Message) bool {
+	for msg != nil {
+		switch {
+		case msg.ViewOnceMessage != nil, msg.ViewOnceMessageV2 != nil, msg.ViewOnceMessageV2Extension != nil:
+			return true
+		case msg.GetImageMessage().GetViewOnce(), msg.GetVideoMessage().GetViewOnce(), msg.GetAudioMessage().GetViewOnce():
+			return true
+		}
+
+		var inner *waE2E.Message
+		switch {
+		case msg.GetDeviceSentMessage().GetMessage() != nil:
+			inner = msg.GetDeviceSentMessage().GetMessage()
+		case msg.GetEphemeralMessage().GetMessage() != nil:
+			inner = msg.GetEphemeralMessage().GetMessage()
+		}
+		msg = inner
+	}
+	return false
+}
+
+func DownloadViewOnce(ctx context.Context, client *whatsmeow.Client, msg *waE2E.Message) (MediaType, []byte, SendOptions, error) {
+	inner := UnwrapMessage(msg)
+	var mediaType MediaType
+	var opts SendOptions
+
+	switch {
+	case inner.GetImageMessage() != nil:
+		mediaType = MediaImage
+		opts.Caption = inner.GetImageMessage().GetCaption()
+	case inner.GetVideoMessage() != nil:
+		mediaType = MediaVideo
+		opts.Caption = inner.GetVideoMessage().GetCaption()
+	case inner.GetAudioMessage() != nil:
+		mediaType = MediaAudio
+		opts.PTT = inner.GetAudioMessage().GetPTT()
+	default:
+		return "", nil, opts, fmt.Errorf("not a view-once image, video or audio")
+	}
+
+	data, err := client.DownloadAny(ctx, inner)
+	if err != nil {
+		return "", nil, opts, err
+	}
+	return mediaType, data, opts, nil
+}
+
+func HandleViewOnce(m *Message) error {
+	if !Config.ANTI_VIEWONCE || !m.IsViewOnce || m.FromMe || m.Data == nil || OwnerJID(m.Client).IsEmpty() {
+		return nil
+	}
+
+	mediaType, data, opts, err := DownloadViewOnce(context.Background(), m.Client, m.Inner)
+	if err != nil {
+		return err
+	}
+
+	owner := &Message{Client: m.Client, Chat: OwnerJID(m.Client)}
+	header := recoveredHeader(m.Client, "View-once message", m.Chat, m.SenderPN, m.Data.Info.Timestamp)
+	if _, err := owner.Send(MediaText, header, SendOptions{Mentions: []types.JID{m.SenderPN}}); err != nil {
+		return err
+	}
+	_, err = owner.Send(mediaType, data, opts)
+	return err
+}
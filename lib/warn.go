@@ -0,0 +1,259 @@
+package lib
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	WarnKick = "kick"
+	WarnMute = "mute"
+)
+
+type Warning struct {
+	ID        int64
+	Chat      types.JID
+	User      types.JID
+	Issuer    types.JID
+	Reason    string
+	CreatedAt time.Time
+}
+
+type WarnSettings struct {
+	Chat   types.JID
+	Limit  int
+	Action string
+}
+
+type WarnResult struct {
+	Count  int
+	Limit  int
+	Action string
+}
+
+var (
+	muteCache = make(map[types.JID]map[string]int64)
+	muteMutex sync.RWMutex
+)
+
+func init() {
+	registerSchema(
+		`CREATE TABLE IF NOT EXISTS warns (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat TEXT NOT NULL,
+			user TEXT NOT NULL,
+			issuer TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS warns_chat_user ON warns (chat, user)`,
+		`CREATE TABLE IF NOT EXISTS warn_settings (
+			chat TEXT PRIMARY KEY,
+			warn_limit INTEGER NOT NULL,
+			action TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS mutes (
+			chat TEXT NOT NULL,
+			user TEXT NOT NULL,
+			until INTEGER NOT NULL,
+			PRIMARY KEY (chat, user)
+		)`,
+	)
+}
+
+func GetWarnSettings(chat types.JID) (*WarnSettings, error) {
+	s := &WarnSettings{Chat: chat}
+	err := DB.QueryRow(`SELECT warn_limit, action FROM warn_settings WHERE chat = ?`, chat.String()).Scan(&s.Limit, &s.Action)
+	if err == sql.ErrNoRows {
+		s.Limit, s.Action = Config.WARN_LIMIT, Config.WARN_ACTION
+	} else if err != nil {
+		return nil, err
+	}
+	if s.Limit < 1 {
+		s.Limit = 3
+	}
+	if s.Action != WarnMute {
+		s.Action = WarnKick
+	}
+	return s, nil
+}
+
+func SaveWarnSettings(s *WarnSettings) error {
+	_, err := DB.Exec(`INSERT OR REPLACE INTO warn_settings (chat, warn_limit, action) VALUES (?, ?, ?)`,
+		s.Chat.String(), s.Limit, s.Action)
+	return err
+}
+
+func AddWarn(client *whatsmeow.Client, chat, user, issuer types.JID, reason string) (*WarnResult, error) {
+	key := MemberKey(client, chat, user)
+	_, err := DB.Exec(`INSERT INTO warns (chat, user, issuer, reason, created_at) VALUES (?, ?, ?, ?, ?)`,
+		chat.String(), key, ToPN(client, issuer).String(), reason, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	warns, err := getWarns(chat, key)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := GetWarnSettings(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WarnResult{Count: len(warns), Limit: settings.Limit}
+	if result.Count < settings.Limit {
+		return result, nil
+	}
+
+	result.Action = settings.Action
+	switch settings.Action {
+	case WarnMute:
+		err = MuteUser(client, chat, user, time.Duration(Config.WARN_MUTE_MINUTES)*time.Minute)
+	default:
+		err = RemoveParticipant(client, chat, user)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to %s: %w", settings.Action, err)
+	}
+	return result, resetWarns(chat, key)
+}
+
+func RemoveWarn(client *whatsmeow.Client, chat, user types.JID) (bool, error) {
+	res, err := DB.Exec(`DELETE FROM warns WHERE id = (SELECT id FROM warns WHERE chat = ? AND user = ? ORDER BY id DESC LIMIT 1)`,
+		chat.String(), MemberKey(client, chat, user))
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func ResetWarns(client *whatsmeow.Client, chat, user types.JID) error {
+	return resetWarns(chat, MemberKey(client, chat, user))
+}
+
+func resetWarns(chat types.JID, key string) error {
+	_, err := DB.Exec(`DELETE FROM warns WHERE chat = ? AND user = ?`, chat.String(), key)
+	return err
+}
+
+func GetWarns(client *whatsmeow.Client, chat, user types.JID) ([]Warning, error) {
+	return getWarns(chat, MemberKey(client, chat, user))
+}
+
+func getWarns(chat types.JID, key string) ([]Warning, error) {
+	rows, err := DB.Query(`SELECT id, issuer, reason, created_at FROM warns WHERE chat = ? AND user = ? ORDER BY id`,
+		chat.String(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warns []Warning
+	for rows.Next() {
+		w := Warning{Chat: chat, User: ParseJID(key)}
+		var issuer string
+		var created int64
+		if err := rows.Scan(&w.ID, &issuer, &w.Reason, &created); err != nil {
+			return nil, err
+		}
+		w.Issuer, _ = types.ParseJID(issuer)
+		w.CreatedAt = time.Unix(created, 0)
+		warns = append(warns, w)
+	}
+	return warns, rows.Err()
+}
+
+func MuteUser(client *whatsmeow.Client, chat, user types.JID, duration time.Duration) error {
+	_, err := DB.Exec(`INSERT OR REPLACE INTO mutes (chat, user, until) VALUES (?, ?, ?)`,
+		chat.String(), MemberKey(client, chat, user), time.Now().Add(duration).Unix())
+	if err == nil {
+		invalidateMutes(chat)
+	}
+	return err
+}
+
+func UnmuteUser(client *whatsmeow.Client, chat, user types.JID) error {
+	_, err := DB.Exec(`DELETE FROM mutes WHERE chat = ? AND user = ?`, chat.String(), MemberKey(client, chat, user))
+	if err == nil {
+		invalidateMutes(chat)
+	}
+	return err
+}
+
+func IsMuted(client *whatsmeow.Client, chat, user types.JID) bool {
+	mutes, err := loadMutes(chat)
+	if err != nil {
+		return false
+	}
+	until, ok := mutes[MemberKey(client, chat, user)]
+	if !ok {
+		return false
+	}
+	if time.Now().Unix() >= until {
+		UnmuteUser(client, chat, user)
+		return false
+	}
+	return true
+}
+
+func loadMutes(chat types.JID) (map[string]int64, error) {
+	muteMutex.RLock()
+	mutes, ok := muteCache[chat]
+	muteMutex.RUnlock()
+	if ok {
+		return mutes, nil
+	}
+
+	rows, err := DB.Query(`SELECT user, until FROM mutes WHERE chat = ?`, chat.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutes = make(map[string]int64)
+	for rows.Next() {
+		var user string
+		var until int64
+		if err := rows.Scan(&user, &until); err != nil {
+			return nil, err
+		}
+		mutes[user] = until
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	muteMutex.Lock()
+	muteCache[chat] = mutes
+	muteMutex.Unlock()
+	return mutes, nil
+}
+
+func invalidateMutes(chat types.JID) {
+	muteMutex.Lock()
+	delete(muteCache, chat)
+	muteMutex.Unlock()
+}
+
+func WarnText(user types.JID, result *WarnResult, reason string, err error) string {
+	text := fmt.Sprintf("_@%s has been warned (%d/%d)_", user.User, result.Count, result.Limit)
+	if reason != "" {
+		text += fmt.Sprintf("\n*Reason:* %s", reason)
+	}
+	switch {
+	case err != nil:
+		text += fmt.Sprintf("\n_%v_", err)
+	case result.Action == WarnKick:
+		text += "\n_Warn limit reached, removed from the group_"
+	case result.Action == WarnMute:
+		text += fmt.Sprintf("\n_Warn limit reached, muted for %d minutes_", Config.WARN_MUTE_MINUTES)
+	}
+	return text
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -32,6 +31,9 @@ var syncMutex sync.Mutex
 
 func main() {
 	lib.LoadConfig()
+	if err := lib.InitDatabase(); err != nil {
+		panic(err)
+	}
 	ctx := context.Background()
 	dbLog := waLog.Noop
 	container, err := sqlstore.New(ctx, "sqlite3", "file:auth.db?_foreign_keys=on", dbLog)
@@ -72,12 +74,7 @@ func main() {
 
 	if client.Store.ID != nil {
 		time.Sleep(5 * time.Second)
-		sudos := strings.Split(lib.Config.SUDO, ",")
-		sudo := sudos[0]
-		if sudo == "" {
-			sudo = client.Store.ID.User
-		}
-		jid := types.NewJID(sudo, types.DefaultUserServer)
+		jid := lib.OwnerJID(client)
 		prefix := lib.GetPrefix()
 		client.SendMessage(context.Background(), jid, &waE2E.Message{
 			Conversation: proto.String(fmt.Sprintf("*BOT CONNECTED*\n\n```PREFIX : %s\nPLUGINS : %d\nVERSION : %s```", prefix, len(lib.Commands), "1.0.0")),
@@ -110,6 +107,30 @@ func eventHandler(evt interface{}) {
 		syncMutex.Unlock()
 		fmt.Println("\x1b[32m[Offline Sync Completed] - Bot is now ready to process commands\x1b[39m")
 
+	case *events.JoinedGroup:
+		lib.HandleGroupEvent(v)
+
+	case *events.GroupInfo:
+		lib.HandleGroupEvent(v)
+		syncMutex.Lock()
+		isSyncCompleted := syncCompleted
+		syncMutex.Unlock()
+		if isSyncCompleted {
+			go lib.HandleGreetings(lib.Client, v)
+		}
+
+	case *events.CallOffer:
+		syncMutex.Lock()
+		isSyncCompleted := syncCompleted
+		syncMutex.Unlock()
+		if isSyncCompleted {
+			go func() {
+				if err := lib.HandleCall(lib.Client, v); err != nil {
+					fmt.Println("Anti-call error:", err)
+				}
+			}()
+		}
+
 	case *events.Message:
 		handleMessage(v)
 	}
@@ -127,6 +148,14 @@ func handleMessage(evt *events.Message) {
 
 	message := lib.NewMessage(lib.Client, evt)
 
+	if err := lib.StoreMessage(evt); err != nil {
+		fmt.Println("Store error:", err)
+	}
+
+	if err := lib.HandlePoll(message); err != nil {
+		fmt.Println("Poll error:", err)
+	}
+
 	if lib.Config.LOG_MSG {
 		fmt.Printf("[%s] : %s\n", message.PushName, message.Text)
 	}
@@ -139,6 +168,34 @@ func handleMessage(evt *events.Message) {
 		lib.Client.MarkRead(ctx, []types.MessageID{evt.Info.ID}, time.Now(), evt.Info.Chat, evt.Info.Sender)
 	}
 
+	if message.IsRevoke {
+		go func() {
+			if err := lib.HandleRevoke(message); err != nil {
+				fmt.Println("Anti-delete error:", err)
+			}
+		}()
+	}
+
+	if message.IsViewOnce {
+		go func() {
+			if err := lib.HandleViewOnce(message); err != nil {
+				fmt.Println("View-once error:", err)
+			}
+		}()
+	}
+
+	if !lib.RunMiddleware(message) {
+		return
+	}
+
+	for _, filter := range lib.MatchFilters(message) {
+		go func(filter *lib.Filter) {
+			if _, err := filter.Respond(message); err != nil {
+				fmt.Println("Filter error:", err)
+			}
+		}(filter)
+	}
+
 	for _, command := range lib.Commands {
 		isMatch := false
 		if command.On != "" {
@@ -151,12 +208,26 @@ func handleMessage(evt *events.Message) {
 				isMatch = message.Type == "stickerMessage"
 			case "audio":
 				isMatch = message.Type == "audioMessage"
+			case "location":
+				isMatch = message.Location != nil || message.LiveLocation != nil
+			case "contact":
+				isMatch = len(message.Contacts) > 0
+			case "poll":
+				isMatch = message.PollUpdate != nil
+			case "reaction":
+				isMatch = message.Reaction != nil
+			case "edit":
+				isMatch = message.IsEdit
+			case "delete":
+				isMatch = message.IsRevoke
+			case "viewonce":
+				isMatch = message.IsViewOnce
 			case "text":
-				isMatch = message.Text != ""
+				isMatch = message.Text != "" && (!message.IsEdit || lib.Config.EDIT_CMD)
 			case "message":
-				isMatch = true
+				isMatch = !message.IsEdit || lib.Config.EDIT_CMD
 			}
-		} else if command.Pattern != nil {
+		} else if command.Pattern != nil && (!message.IsEdit || lib.Config.EDIT_CMD) {
 			isMatch = command.Pattern.MatchString(message.Text)
 		}
 
@@ -196,17 +267,19 @@ func handleMessage(evt *events.Message) {
 				}
 			}
 
+			react := command.Pattern != nil && command.React && lib.Config.CMD_REACT
 			go func() {
+				if react {
+					message.React("⏳")
+				}
 				defer func() {
 					if r := recover(); r != nil {
+						if react {
+							message.React("❌")
+						}
 						if lib.Config.ERROR_MSG {
 							fmt.Println("Error:", r)
-							sudos := strings.Split(lib.Config.SUDO, ",")
-							sudo := sudos[0]
-							if sudo == "" {
-								sudo = lib.Client.Store.ID.User
-							}
-							jid := types.NewJID(sudo, types.DefaultUserServer)
+							jid := lib.OwnerJID(lib.Client)
 							lib.Client.SendMessage(context.Background(), jid, &waE2E.Message{
 								Conversation: proto.String(fmt.Sprintf("```─━❲ ERROR REPORT ❳━─\n\nMessage : %s\nError : %v\nJid : %s```", message.Text, r, message.Chat.String())),
 							})
@@ -214,6 +287,9 @@ func handleMessage(evt *events.Message) {
 					}
 				}()
 				command.Function(message, match)
+				if react && message.LastReaction() == "⏳" {
+					message.React("✅")
+				}
 			}()
 		}
 	}
@@ -0,0 +1,87 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gobot/lib"
+)
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "anticall ?(.*)",
+		"desc":    "Reject incoming calls and block repeat callers",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		policy, err := lib.GetCallPolicy()
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load call policy: %v_", err))
+			return
+		}
+
+		match = strings.TrimSpace(match)
+		command, arg, _ := strings.Cut(match, " ")
+		arg = strings.TrimSpace(arg)
+
+		reply := ""
+		switch strings.ToLower(command) {
+		case "":
+			text := policy.Message
+			if text == "" {
+				text = "none"
+			}
+			message.Reply(fmt.Sprintf("*Anti-call:* %s\n*Block after:* %d calls within %d minutes\n*Message:* %s\n\n_Usage: .anticall off | reject | block [calls] [minutes] | msg <text> | msg off_",
+				policy.Mode, policy.Limit, policy.Window, text))
+			return
+		case lib.CallOff, lib.CallReject:
+			policy.Mode = strings.ToLower(command)
+			reply = fmt.Sprintf("_Anti-call set to %s_", policy.Mode)
+		case lib.CallBlock:
+			args := strings.Fields(arg)
+			if len(args) > 2 {
+				message.Reply("_Too many values!_\n*Example: .anticall block 3 60*")
+				return
+			}
+			if len(args) > 0 {
+				limit, err := strconv.Atoi(args[0])
+				if err != nil || limit < 1 {
+					message.Reply("_Invalid number of calls!_\n*Example: .anticall block 3*")
+					return
+				}
+				policy.Limit = limit
+			}
+			if len(args) > 1 {
+				window, err := strconv.Atoi(args[1])
+				if err != nil || window < 1 {
+					message.Reply("_Invalid number of minutes!_\n*Example: .anticall block 3 60*")
+					return
+				}
+				policy.Window = window
+			}
+			policy.Mode = lib.CallBlock
+			reply = fmt.Sprintf("_Calls will be rejected, callers blocked after %d calls within %d minutes_", policy.Limit, policy.Window)
+		case "msg":
+			if arg == "" {
+				message.Reply("_Need a message!_\n*Example: .anticall msg Calls are not allowed*")
+				return
+			}
+			if strings.EqualFold(arg, "off") {
+				policy.Message = ""
+				reply = "_Call message disabled_"
+			} else {
+				policy.Message = arg
+				reply = "_Call message updated_"
+			}
+		default:
+			message.Reply("_Invalid option!_\n*Example: .anticall off | reject | block 3 | msg <text>*")
+			return
+		}
+
+		if err := lib.SaveCallPolicy(policy); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to save call policy: %v_", err))
+			return
+		}
+		message.Reply(reply)
+	})
+}
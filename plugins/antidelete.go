@@ -0,0 +1,82 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gobot/lib"
+)
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "antidelete ?(.*)",
+		"desc":    "Repost deleted messages in this chat or to your DM",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		usage := "chat | owner | off | admins on | admins off | hours <n>"
+
+		settings, err := lib.GetAntiDelete(message.Chat)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load anti-delete: %v_", err))
+			return
+		}
+
+		command, arg, _ := strings.Cut(strings.ToLower(strings.TrimSpace(match)), " ")
+		arg = strings.TrimSpace(arg)
+		if command == "on" {
+			command = lib.AntiDeleteChat
+		}
+
+		var reply string
+		switch command {
+		case "":
+			admins := "off"
+			if settings.Admins {
+				admins = "on"
+			}
+			message.Reply(fmt.Sprintf("*Anti-delete:* %s\n*Admin deletions:* %s\n*Retention:* %d hours\n\n"+
+				"_Usage: .antidelete %s_\n_admins on also recovers messages removed by group admins. "+
+				"hours sets how old a deleted message may be, up to %d hours (MSG_STORE_HOURS)._",
+				settings.Mode, admins, settings.Hours, usage, lib.Config.MSG_STORE_HOURS))
+			return
+		case lib.AntiDeleteChat, lib.AntiDeleteOwner, lib.AntiDeleteOff:
+			settings.Mode = command
+			switch command {
+			case lib.AntiDeleteChat:
+				reply = "_Deleted messages will be reposted here_"
+			case lib.AntiDeleteOwner:
+				reply = "_Deleted messages will be sent to your DM_"
+			default:
+				reply = "_Anti-delete disabled_"
+			}
+		case "admins":
+			if arg != "on" && arg != "off" {
+				message.Reply("_Invalid value!_\n*Example: .antidelete admins on | off*")
+				return
+			}
+			settings.Admins = arg == "on"
+			reply = "_Messages deleted by admins will not be recovered_"
+			if settings.Admins {
+				reply = "_Messages deleted by admins will be recovered too_"
+			}
+		case "hours":
+			hours, err := strconv.Atoi(arg)
+			if err != nil || hours < 1 || hours > lib.Config.MSG_STORE_HOURS {
+				message.Reply(fmt.Sprintf("_Hours must be between 1 and %d!_\n*Example: .antidelete hours 6*", lib.Config.MSG_STORE_HOURS))
+				return
+			}
+			settings.Hours = hours
+			reply = fmt.Sprintf("_Messages deleted within %d hours will be recovered_", hours)
+		default:
+			message.Reply(fmt.Sprintf("_Invalid option!_\n*Example: .antidelete %s*", usage))
+			return
+		}
+
+		if err := lib.SaveAntiDelete(settings); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to save anti-delete: %v_", err))
+			return
+		}
+		message.Reply(reply)
+	})
+}
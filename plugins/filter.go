@@ -0,0 +1,139 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"gobot/lib"
+)
+
+func parseTrigger(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, `"`) {
+		if end := strings.Index(text[1:], `"`); end >= 0 {
+			return text[1 : end+1], strings.TrimSpace(text[end+2:])
+		}
+	}
+	trigger, rest, _ := strings.Cut(text, " ")
+	return trigger, strings.TrimSpace(rest)
+}
+
+func parseFilterFlags(text string) (global bool, match string, rest string) {
+	match = lib.MatchExact
+	fields := strings.Fields(text)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+		switch fields[0] {
+		case "-g":
+			global = true
+		case "-c":
+			match = lib.MatchContains
+		case "-r":
+			match = lib.MatchRegex
+		default:
+			return global, match, strings.TrimSpace(text)
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), fields[0]))
+		fields = fields[1:]
+	}
+	return global, match, strings.TrimSpace(text)
+}
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "filter(?: (.*)|$)",
+		"desc":    "Auto-reply to a trigger, -g global, -c contains, -r regex",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		global, mode, rest := parseFilterFlags(match)
+		trigger, response := parseTrigger(rest)
+		if trigger == "" || (response == "" && message.Quoted == nil) {
+			message.Reply("_Need a trigger and a response!_\n*Example: .filter hi Hello there*\n*Example: .filter -c \"good morning\" Morning!*\n_Reply to a message to use it as the response_")
+			return
+		}
+
+		filter := &lib.Filter{
+			Chat:     lib.FilterScope(message.Chat, global),
+			Trigger:  trigger,
+			Match:    mode,
+			Response: response,
+		}
+		if response == "" {
+			full := message.Quoted.Key().Message
+			if full == nil {
+				message.Reply("_Can't use that message as a response!_")
+				return
+			}
+			if message.Quoted.Type == "conversation" || message.Quoted.Type == "extendedTextMessage" {
+				filter.Response = message.Quoted.Text
+			} else {
+				filter.Message = full
+			}
+		}
+
+		if err := lib.SaveFilter(filter); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to save filter: %v_", err))
+			return
+		}
+		scope := "this chat"
+		if global {
+			scope = "all chats"
+		}
+		message.Reply(fmt.Sprintf("_Filter *%s* (%s) saved for %s_", trigger, mode, scope))
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern": "stop ?(.*)",
+		"desc":    "Remove a filter, -g for a global one",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		global, _, rest := parseFilterFlags(match)
+		trigger, _ := parseTrigger(rest)
+		if trigger == "" {
+			message.Reply("_Need a trigger!_\n*Example: .stop hi*")
+			return
+		}
+
+		removed, err := lib.DeleteFilter(lib.FilterScope(message.Chat, global), trigger)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to remove filter: %v_", err))
+			return
+		}
+		if !removed {
+			message.Reply(fmt.Sprintf("_No filter named *%s*_", trigger))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Filter *%s* removed_", trigger))
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern": "filters",
+		"desc":    "List the filters of this chat",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		var b strings.Builder
+		for _, scope := range []struct{ chat, title string }{{message.Chat.String(), "Chat filters"}, {lib.GlobalChat, "Global filters"}} {
+			filters, err := lib.GetFilters(scope.chat)
+			if err != nil {
+				message.Reply(fmt.Sprintf("_Failed to load filters: %v_", err))
+				return
+			}
+			if len(filters) == 0 {
+				continue
+			}
+			b.WriteString(fmt.Sprintf("*%s*\n", scope.title))
+			for i, f := range filters {
+				kind := "text"
+				if f.Message != nil {
+					kind = "media"
+				}
+				b.WriteString(fmt.Sprintf("%d. %s _(%s, %s)_\n", i+1, f.Trigger, f.Match, kind))
+			}
+			b.WriteString("\n")
+		}
+		if b.Len() == 0 {
+			message.Reply("_No filters_")
+			return
+		}
+		message.Reply(strings.TrimSpace(b.String()))
+	})
+}
@@ -0,0 +1,56 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"gobot/lib"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "forward ?(.*)",
+		"fromMe":  lib.Mode(),
+		"desc":    "Forward the quoted message to one or more chats",
+		"type":    "whatsapp",
+	}, func(message *lib.Message, match string) {
+		if !message.FromMe && !message.IsSudo {
+			return
+		}
+		if message.Quoted == nil {
+			message.Reply("_Reply to a message to forward!_")
+			return
+		}
+
+		var targets []types.JID
+		for _, field := range strings.FieldsFunc(match, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if strings.HasPrefix(field, "@") {
+				continue
+			}
+			if jid := lib.ParseJID(field); !jid.IsEmpty() {
+				targets = append(targets, jid)
+			}
+		}
+		targets = append(targets, message.MentionedJid...)
+
+		if len(targets) == 0 {
+			message.Reply("_Need a jid!_\n*Example: .forward 91xxxxxxxxxx@s.whatsapp.net,xxxx@g.us*")
+			return
+		}
+
+		var failed []string
+		for _, jid := range targets {
+			if _, err := message.Quoted.Forward(jid); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", jid.String(), err))
+			}
+		}
+
+		if len(failed) > 0 {
+			message.Reply(fmt.Sprintf("_Failed to forward to:_\n%s", strings.Join(failed, "\n")))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Forwarded to %d chat(s)_", len(targets)))
+	})
+}
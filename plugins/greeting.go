@@ -0,0 +1,77 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"gobot/lib"
+)
+
+func init() {
+	for _, kind := range []string{lib.GreetingWelcome, lib.GreetingGoodbye} {
+		kind := kind
+		lib.Function(map[string]interface{}{
+			"pattern":   kind + " ?(.*)",
+			"fromMe":    lib.Mode(),
+			"onlyGroup": true,
+			"desc":      fmt.Sprintf("Configure the %s message of this group", kind),
+			"type":      "group",
+		}, func(message *lib.Message, match string) {
+			configureGreeting(message, kind, strings.TrimSpace(match))
+		})
+	}
+}
+
+func configureGreeting(message *lib.Message, kind, match string) {
+	if !message.FromMe && !message.IsSudo && !message.IsAdmin() {
+		message.Reply("_You're not an admin!_")
+		return
+	}
+
+	g, err := lib.GetGreeting(message.Chat, kind)
+	if err != nil {
+		message.Reply(fmt.Sprintf("_Failed to load %s message: %v_", kind, err))
+		return
+	}
+
+	reply := ""
+	switch strings.ToLower(match) {
+	case "":
+		status := "off"
+		if g.Enabled {
+			status = "on"
+		}
+		pp := "off"
+		if g.ProfilePic {
+			pp = "on"
+		}
+		message.Reply(fmt.Sprintf("*%s:* %s\n*Profile picture:* %s\n\n%s\n\n_Usage: .%s on | off | pp on | pp off | reset | <text>_\n_Placeholders: @user, {group}, {count}, {desc}_",
+			strings.ToUpper(kind), status, pp, g.Template, kind))
+		return
+	case "on":
+		g.Enabled = true
+		reply = fmt.Sprintf("_%s message enabled_", kind)
+	case "off":
+		g.Enabled = false
+		reply = fmt.Sprintf("_%s message disabled_", kind)
+	case "pp on":
+		g.ProfilePic = true
+		reply = "_Profile picture enabled_"
+	case "pp off":
+		g.ProfilePic = false
+		reply = "_Profile picture disabled_"
+	case "reset":
+		g.Template = ""
+		reply = fmt.Sprintf("_%s message reset to default_", kind)
+	default:
+		g.Template = match
+		g.Enabled = true
+		reply = fmt.Sprintf("_%s message updated_", kind)
+	}
+
+	if err := lib.SaveGreeting(g); err != nil {
+		message.Reply(fmt.Sprintf("_Failed to save %s message: %v_", kind, err))
+		return
+	}
+	message.Reply(reply)
+}
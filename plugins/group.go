@@ -0,0 +1,368 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gobot/lib"
+
+	"github.com/disintegration/imaging"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var numberRegex = regexp.MustCompile(`\d{5,16}`)
+
+func requireAdmin(message *lib.Message) (*types.GroupInfo, bool) {
+	info, err := message.GroupInfo()
+	if err != nil {
+		message.Reply(fmt.Sprintf("_Failed to fetch group info: %v_", err))
+		return nil, false
+	}
+	if !message.IsBotAdmin() {
+		message.Reply("_I'm not an admin!_")
+		return nil, false
+	}
+	if !message.FromMe && !message.IsAdmin() {
+		message.Reply("_You're not an admin!_")
+		return nil, false
+	}
+	return info, true
+}
+
+func targetUsers(message *lib.Message, match string) []types.JID {
+	var users []types.JID
+	users = append(users, message.MentionedJid...)
+	if message.Quoted != nil {
+		users = append(users, message.Quoted.Sender)
+	}
+	if len(message.MentionedJid) == 0 {
+		for _, number := range numberRegex.FindAllString(match, -1) {
+			users = append(users, types.NewJID(number, types.DefaultUserServer))
+		}
+	}
+	return users
+}
+
+func updateParticipants(message *lib.Message, match string, action whatsmeow.ParticipantChange, verb, past string) {
+	if _, ok := requireAdmin(message); !ok {
+		return
+	}
+
+	users := targetUsers(message, match)
+	if len(users) == 0 {
+		message.Reply(fmt.Sprintf("_Mention, reply to or give the number of a user to %s!_", verb))
+		return
+	}
+
+	var targets []types.JID
+	for _, user := range users {
+		if lib.IsOwnJID(message.Client, user) {
+			continue
+		}
+		targets = append(targets, user)
+	}
+	if len(targets) == 0 {
+		message.Reply(fmt.Sprintf("_I can't %s myself!_", verb))
+		return
+	}
+
+	results, err := lib.UpdateParticipants(message.Client, message.Chat, targets, action)
+	if err != nil {
+		message.Reply(fmt.Sprintf("_Failed to %s: %v_", verb, err))
+		return
+	}
+
+	var done, failed []string
+	var mentions []types.JID
+	for _, result := range results {
+		if result.Error != 0 {
+			failed = append(failed, fmt.Sprintf("@%s (%d)", result.JID.User, result.Error))
+		} else {
+			done = append(done, "@"+result.JID.User)
+		}
+		mentions = append(mentions, result.JID)
+	}
+
+	text := ""
+	if len(done) > 0 {
+		text = fmt.Sprintf("_%s: %s_", past, strings.Join(done, ", "))
+	}
+	if len(failed) > 0 {
+		text = strings.TrimSpace(text + fmt.Sprintf("\n_Failed to %s: %s_", verb, strings.Join(failed, ", ")))
+	}
+	message.Send("text", text, lib.SendOptions{Mentions: mentions})
+}
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern":   "kick ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Remove a member from the group",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		updateParticipants(message, match, whatsmeow.ParticipantChangeRemove, "kick", "Kicked")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "add ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Add a member to the group",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		updateParticipants(message, match, whatsmeow.ParticipantChangeAdd, "add", "Added")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "promote ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Make a member an admin",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		updateParticipants(message, match, whatsmeow.ParticipantChangePromote, "promote", "Promoted")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "demote ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Remove admin rights from a member",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		updateParticipants(message, match, whatsmeow.ParticipantChangeDemote, "demote", "Demoted")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "mute",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Allow only admins to send messages",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupAnnounce(context.Background(), message.Chat, true)
+		}, "_Group muted_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "unmute",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Allow all members to send messages",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupAnnounce(context.Background(), message.Chat, false)
+		}, "_Group unmuted_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "lock",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Allow only admins to edit group info",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupLocked(context.Background(), message.Chat, true)
+		}, "_Group settings locked_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "unlock",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Allow all members to edit group info",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupLocked(context.Background(), message.Chat, false)
+		}, "_Group settings unlocked_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "invite",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Get the group invite link",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+		link, err := message.Client.GetGroupInviteLink(context.Background(), message.Chat, false)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to get invite link: %v_", err))
+			return
+		}
+		message.Reply(link)
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "revoke",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Reset the group invite link",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+		link, err := message.Client.GetGroupInviteLink(context.Background(), message.Chat, true)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to revoke invite link: %v_", err))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Invite link revoked_\n%s", link))
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "gname ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Change the group name",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		name := strings.TrimSpace(match)
+		if name == "" && message.Quoted != nil {
+			name = message.Quoted.Text
+		}
+		if name == "" {
+			message.Reply("_Need a name!_\n*Example: .gname My Group*")
+			return
+		}
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupName(context.Background(), message.Chat, name)
+		}, "_Group name updated_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "gdesc ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Change the group description",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		desc := strings.TrimSpace(match)
+		if desc == "" && message.Quoted != nil {
+			desc = message.Quoted.Text
+		}
+		if desc == "" {
+			message.Reply("_Need a description!_\n*Example: .gdesc Welcome everyone*")
+			return
+		}
+		setGroupSetting(message, func() error {
+			return message.Client.SetGroupTopic(context.Background(), message.Chat, "", "", desc)
+		}, "_Group description updated_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "gpp",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Change the group picture",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if message.Quoted == nil || message.Quoted.Type != "imageMessage" {
+			message.Reply("_Reply to an image!_")
+			return
+		}
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+
+		data, err := message.Client.DownloadAny(context.Background(), message.Quoted.Inner)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to download image: %v_", err))
+			return
+		}
+
+		img, err := imaging.Decode(bytes.NewReader(data))
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Invalid image: %v_", err))
+			return
+		}
+		buf := new(bytes.Buffer)
+		if err := imaging.Encode(buf, imaging.Fill(img, 640, 640, imaging.Center, imaging.Lanczos), imaging.JPEG); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to process image: %v_", err))
+			return
+		}
+
+		if _, err := message.Client.SetGroupPhoto(context.Background(), message.Chat, buf.Bytes()); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to update group picture: %v_", err))
+			return
+		}
+		message.Reply("_Group picture updated_")
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "tagall ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Mention every member of the group",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		info, ok := requireAdmin(message)
+		if !ok {
+			return
+		}
+
+		var b strings.Builder
+		if text := strings.TrimSpace(match); text != "" {
+			b.WriteString(text + "\n\n")
+		}
+		mentions := make([]types.JID, 0, len(info.Participants))
+		for _, p := range info.Participants {
+			b.WriteString(fmt.Sprintf("➥ @%s\n", p.JID.User))
+			mentions = append(mentions, p.JID)
+		}
+
+		message.Send("text", strings.TrimSpace(b.String()), lib.SendOptions{Mentions: mentions})
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "hidetag ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Mention every member without listing them",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		info, ok := requireAdmin(message)
+		if !ok {
+			return
+		}
+
+		text := strings.TrimSpace(match)
+		if text == "" && message.Quoted != nil {
+			text = message.Quoted.Text
+		}
+		if text == "" {
+			message.Reply("_Need a message!_\n*Example: .hidetag Hello everyone*")
+			return
+		}
+
+		mentions := make([]types.JID, 0, len(info.Participants))
+		for _, p := range info.Participants {
+			mentions = append(mentions, p.JID)
+		}
+
+		message.Send("text", text, lib.SendOptions{Mentions: mentions})
+	})
+}
+
+func setGroupSetting(message *lib.Message, apply func() error, done string) {
+	if _, ok := requireAdmin(message); !ok {
+		return
+	}
+	if err := apply(); err != nil {
+		message.Reply(fmt.Sprintf("_Failed: %v_", err))
+		return
+	}
+	message.Reply(done)
+}
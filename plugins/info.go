@@ -15,9 +15,12 @@ func init() {
 		"type":    "info",
 	}, func(message *lib.Message, match string) {
 		start := time.Now()
-		message.Reply("*Ping!*")
+		sent, err := message.Reply("*Ping!*")
 		responseTime := time.Since(start).Milliseconds()
-		message.Reply(fmt.Sprintf("*Pong!*\nLatency: %dms", responseTime))
+		if err != nil {
+			return
+		}
+		sent.Edit(fmt.Sprintf("*Pong!*\nLatency: %dms", responseTime))
 	})
 
 	lib.Function(map[string]interface{}{
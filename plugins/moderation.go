@@ -0,0 +1,188 @@
+package plugins
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gobot/lib"
+)
+
+var spamLimitRegex = regexp.MustCompile(`^(\d+)\s*/\s*(\d+)$`)
+
+var moderationDesc = map[string]string{
+	lib.FilterAntiLink:    "Block links and group invites in this group",
+	lib.FilterAntiSpam:    "Block message bursts in this group",
+	lib.FilterAntiWord:    "Block banned words in this group",
+	lib.FilterAntiForward: "Block forwarded messages in this group",
+}
+
+var moderationUsage = map[string]string{
+	lib.FilterAntiLink:    "allow <domains> | disallow <domains>",
+	lib.FilterAntiSpam:    "limit <messages>/<seconds>",
+	lib.FilterAntiWord:    "add <words> | remove <words>",
+	lib.FilterAntiForward: "",
+}
+
+func init() {
+	lib.Use(lib.Moderate)
+
+	for _, filter := range lib.Filters {
+		filter := filter
+		lib.Function(map[string]interface{}{
+			"pattern":   filter + " ?(.*)",
+			"fromMe":    lib.Mode(),
+			"onlyGroup": true,
+			"desc":      moderationDesc[filter],
+			"type":      "group",
+		}, func(message *lib.Message, match string) {
+			configureModeration(message, filter, strings.TrimSpace(match))
+		})
+	}
+}
+
+func configureModeration(message *lib.Message, filter, match string) {
+	if !message.FromMe && !message.IsSudo && !message.IsAdmin() {
+		message.Reply("_You're not an admin!_")
+		return
+	}
+
+	settings, err := lib.GetModeration(message.Chat)
+	if err != nil {
+		message.Reply(fmt.Sprintf("_Failed to load %s settings: %v_", filter, err))
+		return
+	}
+	s := settings[filter]
+
+	command, arg, _ := strings.Cut(match, " ")
+	arg = strings.TrimSpace(arg)
+
+	reply := ""
+	switch strings.ToLower(command) {
+	case "":
+		message.Reply(moderationStatus(s))
+		return
+	case "on":
+		if !message.IsBotAdmin() {
+			message.Reply("_I'm not an admin!_")
+			return
+		}
+		s.Enabled = true
+		reply = fmt.Sprintf("_%s enabled_", filter)
+	case "off":
+		s.Enabled = false
+		reply = fmt.Sprintf("_%s disabled_", filter)
+	case "action":
+		action := strings.ToLower(arg)
+		if action != lib.ActionWarn && action != lib.ActionDelete && action != lib.ActionKick {
+			message.Reply(fmt.Sprintf("_Invalid action!_\n*Example: .%s action warn | delete | kick*", filter))
+			return
+		}
+		s.Action = action
+		reply = fmt.Sprintf("_%s action set to %s_", filter, action)
+	case "allow", "add":
+		if filter != lib.FilterAntiLink && filter != lib.FilterAntiWord {
+			message.Reply(moderationStatus(s))
+			return
+		}
+		values := splitValues(arg)
+		if len(values) == 0 {
+			message.Reply(fmt.Sprintf("_Need a value!_\n*Example: .%s %s example.com*", filter, command))
+			return
+		}
+		for _, value := range values {
+			if !containsFold(s.Values, value) {
+				s.Values = append(s.Values, value)
+			}
+		}
+		reply = fmt.Sprintf("_Added: %s_", strings.Join(values, ", "))
+	case "disallow", "remove":
+		if filter != lib.FilterAntiLink && filter != lib.FilterAntiWord {
+			message.Reply(moderationStatus(s))
+			return
+		}
+		values := splitValues(arg)
+		var kept []string
+		for _, value := range s.Values {
+			if !containsFold(values, value) {
+				kept = append(kept, value)
+			}
+		}
+		s.Values = kept
+		reply = fmt.Sprintf("_Removed: %s_", strings.Join(values, ", "))
+	case "limit":
+		m := spamLimitRegex.FindStringSubmatch(arg)
+		if filter != lib.FilterAntiSpam || m == nil || !inRange(m[1], 1, 100) || !inRange(m[2], 1, 600) {
+			message.Reply("_Invalid limit!_\n*Example: .antispam limit 5/10 (up to 600 seconds)*")
+			return
+		}
+		s.Values = []string{m[1], m[2]}
+		reply = fmt.Sprintf("_%s limit set to %s messages in %s seconds_", filter, m[1], m[2])
+	default:
+		message.Reply(moderationStatus(s))
+		return
+	}
+
+	if err := lib.SaveModeration(s); err != nil {
+		message.Reply(fmt.Sprintf("_Failed to save %s settings: %v_", filter, err))
+		return
+	}
+	message.Reply(reply)
+}
+
+func moderationStatus(s *lib.ModerationSetting) string {
+	status := "off"
+	if s.Enabled {
+		status = "on"
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%s:* %s\n*Action:* %s\n", strings.ToUpper(s.Filter), status, s.Action))
+	switch s.Filter {
+	case lib.FilterAntiLink:
+		b.WriteString(fmt.Sprintf("*Allowed:* %s\n", listOrNone(s.Values)))
+	case lib.FilterAntiWord:
+		b.WriteString(fmt.Sprintf("*Words:* %s\n", listOrNone(s.Values)))
+	case lib.FilterAntiSpam:
+		if len(s.Values) == 2 {
+			b.WriteString(fmt.Sprintf("*Limit:* %s messages in %s seconds\n", s.Values[0], s.Values[1]))
+		}
+	}
+
+	usage := "on | off | action warn | action delete | action kick"
+	if extra := moderationUsage[s.Filter]; extra != "" {
+		usage += " | " + extra
+	}
+	b.WriteString(fmt.Sprintf("\n_Usage: .%s %s_", s.Filter, usage))
+	return b.String()
+}
+
+func splitValues(arg string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' }) {
+		values = append(values, strings.ToLower(value))
+	}
+	return values
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+func inRange(value string, min, max int) bool {
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= min && n <= max
+}
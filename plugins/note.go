@@ -0,0 +1,145 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gobot/lib"
+)
+
+var noteNameRegex = regexp.MustCompile(`^#(\S+)$`)
+
+func sendNote(message *lib.Message, name string) {
+	note, err := lib.GetNote(message.Chat, name)
+	if err != nil {
+		message.Reply(fmt.Sprintf("_Failed to load note: %v_", err))
+		return
+	}
+	if note == nil {
+		message.Reply(fmt.Sprintf("_No note named *%s*_", name))
+		return
+	}
+	if _, err := message.SendNote(note); err != nil {
+		message.Reply(fmt.Sprintf("_Failed to send note: %v_", err))
+	}
+}
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "save ?(.*)",
+		"fromMe":  lib.Mode(),
+		"desc":    "Save the replied message as a note",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		if !message.FromMe && !message.IsSudo && !message.IsAdmin() {
+			message.Reply("_You're not an admin!_")
+			return
+		}
+		name, text, _ := strings.Cut(strings.TrimSpace(match), " ")
+		text = strings.TrimSpace(text)
+		if name == "" || (message.Quoted == nil && text == "") {
+			message.Reply("_Reply to a message with a note name!_\n*Example: .save rules*\n*Example: .save rules Be nice*")
+			return
+		}
+
+		var note *lib.Note
+		if message.Quoted != nil {
+			var err error
+			if note, err = lib.NewNote(context.Background(), message.Quoted, name); err != nil {
+				message.Reply(fmt.Sprintf("_Failed to save note: %v_", err))
+				return
+			}
+		} else {
+			note = &lib.Note{Chat: message.Chat, Name: name, Type: lib.MediaText, Text: text, Mentions: message.MentionedJid}
+		}
+
+		if err := lib.SaveNote(note); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to save note: %v_", err))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Note saved, get it with #%s_", strings.ToLower(name)))
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern": "get ?(.*)",
+		"fromMe":  lib.Mode(),
+		"desc":    "Send a saved note",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		name := strings.TrimPrefix(strings.TrimSpace(match), "#")
+		if name == "" {
+			message.Reply("_Need a note name!_\n*Example: .get rules*")
+			return
+		}
+		sendNote(message, name)
+	})
+
+	lib.Function(map[string]interface{}{
+		"on":     "text",
+		"fromMe": lib.Mode(),
+	}, func(message *lib.Message, match string) {
+		m := noteNameRegex.FindStringSubmatch(strings.TrimSpace(message.Text))
+		if m == nil {
+			return
+		}
+		note, err := lib.GetNote(message.Chat, m[1])
+		if err != nil || note == nil {
+			return
+		}
+		message.SendNote(note)
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern": "notes",
+		"fromMe":  lib.Mode(),
+		"desc":    "List the notes of this chat",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		notes, err := lib.ListNotes(message.Chat)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load notes: %v_", err))
+			return
+		}
+		if len(notes) == 0 {
+			message.Reply("_No notes_")
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString("*Notes*\n")
+		for i, n := range notes {
+			b.WriteString(fmt.Sprintf("%d. #%s _(%s)_\n", i+1, n.Name, n.Type))
+		}
+		message.Reply(strings.TrimSpace(b.String()))
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern": "clear ?(.*)",
+		"fromMe":  lib.Mode(),
+		"desc":    "Delete a saved note",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		if !message.FromMe && !message.IsSudo && !message.IsAdmin() {
+			message.Reply("_You're not an admin!_")
+			return
+		}
+		name := strings.TrimPrefix(strings.TrimSpace(match), "#")
+		if name == "" {
+			message.Reply("_Need a note name!_\n*Example: .clear rules*")
+			return
+		}
+
+		removed, err := lib.DeleteNote(message.Chat, name)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to delete note: %v_", err))
+			return
+		}
+		if !removed {
+			message.Reply(fmt.Sprintf("_No note named *%s*_", name))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Note *%s* deleted_", name))
+	})
+}
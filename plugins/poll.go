@@ -0,0 +1,78 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"gobot/lib"
+)
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "poll ?(.*)",
+		"fromMe":  lib.Mode(),
+		"desc":    "Create a poll or show results of a quoted poll",
+		"type":    "whatsapp",
+	}, func(message *lib.Message, match string) {
+		match = strings.TrimSpace(match)
+
+		if match == "" && message.Quoted != nil {
+			poll, err := lib.GetPoll(message.Quoted.ID)
+			if err != nil || poll == nil {
+				message.Reply("_Reply to a poll created while the bot was online_")
+				return
+			}
+			tally, err := lib.PollTally(poll)
+			if err != nil {
+				message.Reply(fmt.Sprintf("_Failed to load votes: %v_", err))
+				return
+			}
+			message.Reply(formatTally(poll.Question, tally))
+			return
+		}
+
+		selectable := 1
+		if strings.HasPrefix(match, "-m ") {
+			selectable = 0
+			match = strings.TrimSpace(strings.TrimPrefix(match, "-m "))
+		}
+
+		parts := strings.Split(match, "|")
+		var options []string
+		for _, part := range parts[1:] {
+			if option := strings.TrimSpace(part); option != "" {
+				options = append(options, option)
+			}
+		}
+		question := strings.TrimSpace(parts[0])
+
+		if question == "" || len(options) < 2 {
+			message.Reply("_Need a question and at least two options!_\n*Example: .poll Lunch? | Pizza | Burger*\n*Multiple choice: .poll -m Lunch? | Pizza | Burger*")
+			return
+		}
+
+		if _, err := message.SendPoll(question, options, selectable); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to create poll: %v_", err))
+		}
+	})
+
+}
+
+func formatTally(question string, tally []lib.PollOptionCount) string {
+	total := 0
+	for _, option := range tally {
+		total += option.Votes
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%s*\n\n", question))
+	for _, option := range tally {
+		percent := 0
+		if total > 0 {
+			percent = option.Votes * 100 / total
+		}
+		b.WriteString(fmt.Sprintf("%s\n_%d vote(s) • %d%%_\n\n", option.Name, option.Votes, percent))
+	}
+	b.WriteString(fmt.Sprintf("_Total votes: %d_", total))
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	"gobot/lib"
+)
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern": "vv",
+		"desc":    "Re-send a view-once image, video or audio as normal media",
+		"type":    "misc",
+	}, func(message *lib.Message, match string) {
+		if message.Quoted == nil || !message.Quoted.IsViewOnce {
+			message.Reply("_Reply to a view-once message!_")
+			return
+		}
+
+		mediaType, data, opts, err := lib.DownloadViewOnce(context.Background(), message.Client, message.Quoted.Message)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to download view-once media: %v_", err))
+			return
+		}
+
+		opts.Quoted = message
+		if _, err := message.Send(mediaType, data, opts); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to send media: %v_", err))
+		}
+	})
+}
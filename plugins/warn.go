@@ -0,0 +1,188 @@
+package plugins
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gobot/lib"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+var mentionTextRegex = regexp.MustCompile(`@?\+?\d{5,16}`)
+
+func warnTarget(message *lib.Message, match, verb string) (types.JID, bool) {
+	users := targetUsers(message, match)
+	if len(users) == 0 {
+		message.Reply(fmt.Sprintf("_Mention or reply to a user to %s!_", verb))
+		return types.EmptyJID, false
+	}
+	if lib.IsOwnJID(message.Client, users[0]) {
+		message.Reply(fmt.Sprintf("_I can't %s myself!_", verb))
+		return types.EmptyJID, false
+	}
+	return users[0], true
+}
+
+func init() {
+	lib.Function(map[string]interface{}{
+		"pattern":   "warn(?: (.*)|$)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Warn a member of the group",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+		user, ok := warnTarget(message, match, "warn")
+		if !ok {
+			return
+		}
+
+		reason := strings.TrimSpace(mentionTextRegex.ReplaceAllString(match, ""))
+		result, err := lib.AddWarn(message.Client, message.Chat, user, message.Sender, reason)
+		if result == nil {
+			message.Reply(fmt.Sprintf("_Failed to warn: %v_", err))
+			return
+		}
+		message.Send("text", lib.WarnText(user, result, reason, err), lib.SendOptions{Mentions: []types.JID{user}})
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "unwarn ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Remove the latest warning of a member",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+		user, ok := warnTarget(message, match, "unwarn")
+		if !ok {
+			return
+		}
+
+		removed, err := lib.RemoveWarn(message.Client, message.Chat, user)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to remove warning: %v_", err))
+			return
+		}
+		if !removed {
+			message.Send("text", fmt.Sprintf("_@%s has no warnings_", user.User), lib.SendOptions{Mentions: []types.JID{user}})
+			return
+		}
+		message.Send("text", fmt.Sprintf("_Removed a warning from @%s_", user.User), lib.SendOptions{Mentions: []types.JID{user}})
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "warns ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "List the warnings of a member",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		user := message.Sender
+		if users := targetUsers(message, match); len(users) > 0 {
+			user = users[0]
+		}
+
+		warns, err := lib.GetWarns(message.Client, message.Chat, user)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load warnings: %v_", err))
+			return
+		}
+		settings, err := lib.GetWarnSettings(message.Chat)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load warn settings: %v_", err))
+			return
+		}
+		if len(warns) == 0 {
+			message.Send("text", fmt.Sprintf("_@%s has no warnings_", user.User), lib.SendOptions{Mentions: []types.JID{user}})
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("*Warnings for @%s (%d/%d)*\n", user.User, len(warns), settings.Limit))
+		mentions := []types.JID{user}
+		for i, w := range warns {
+			reason := w.Reason
+			if reason == "" {
+				reason = "no reason"
+			}
+			b.WriteString(fmt.Sprintf("\n%d. %s\n   _by @%s on %s_", i+1, reason, w.Issuer.User, w.CreatedAt.Format("2006-01-02 15:04")))
+			mentions = append(mentions, w.Issuer)
+		}
+		message.Send("text", b.String(), lib.SendOptions{Mentions: mentions})
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "resetwarn ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Clear all warnings of a member",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+		user, ok := warnTarget(message, match, "reset")
+		if !ok {
+			return
+		}
+
+		if err := lib.ResetWarns(message.Client, message.Chat, user); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to reset warnings: %v_", err))
+			return
+		}
+		lib.UnmuteUser(message.Client, message.Chat, user)
+		message.Send("text", fmt.Sprintf("_Warnings of @%s have been reset_", user.User), lib.SendOptions{Mentions: []types.JID{user}})
+	})
+
+	lib.Function(map[string]interface{}{
+		"pattern":   "setwarn ?(.*)",
+		"fromMe":    lib.Mode(),
+		"onlyGroup": true,
+		"desc":      "Set the warn limit and the action taken when it is reached",
+		"type":      "group",
+	}, func(message *lib.Message, match string) {
+		if _, ok := requireAdmin(message); !ok {
+			return
+		}
+
+		settings, err := lib.GetWarnSettings(message.Chat)
+		if err != nil {
+			message.Reply(fmt.Sprintf("_Failed to load warn settings: %v_", err))
+			return
+		}
+
+		fields := strings.Fields(strings.ToLower(match))
+		if len(fields) == 0 {
+			message.Reply(fmt.Sprintf("*Warn limit:* %d\n*Action:* %s\n\n_Usage: .setwarn <limit> [kick | mute]_", settings.Limit, settings.Action))
+			return
+		}
+
+		limit, err := strconv.Atoi(fields[0])
+		if err != nil || limit < 1 {
+			message.Reply("_Invalid limit!_\n*Example: .setwarn 3 kick*")
+			return
+		}
+		settings.Limit = limit
+		if len(fields) > 1 {
+			if fields[1] != lib.WarnKick && fields[1] != lib.WarnMute {
+				message.Reply("_Invalid action!_\n*Example: .setwarn 3 mute*")
+				return
+			}
+			settings.Action = fields[1]
+		}
+
+		if err := lib.SaveWarnSettings(settings); err != nil {
+			message.Reply(fmt.Sprintf("_Failed to save warn settings: %v_", err))
+			return
+		}
+		message.Reply(fmt.Sprintf("_Warn limit set to %d, action: %s_", settings.Limit, settings.Action))
+	})
+}
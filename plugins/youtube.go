@@ -56,7 +56,7 @@ func getJSON(apiURL string, target interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(target)
 }
 
-func downloadBuffer(fileURL string) ([]byte, error) {
+func downloadBuffer(fileURL string, progress *lib.Progress, label string) ([]byte, error) {
 	resp, err := http.Get(fileURL)
 	if err != nil {
 		return nil, err
@@ -67,7 +67,7 @@ func downloadBuffer(fileURL string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to download: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return io.ReadAll(progress.Reader(resp.Body, resp.ContentLength, label))
 }
 
 func init() {
@@ -76,15 +76,18 @@ func init() {
 		"fromMe":  lib.Mode(),
 		"desc":    "Download audio from YouTube",
 		"type":    "download",
+		"react":   true,
 	}, func(message *lib.Message, match string) {
 		if match == "" && message.Quoted != nil {
 			match = message.Quoted.Text
 		}
 		if match == "" {
-			message.Reply("_Need URL or song name!_\n*Example: .song URL/song name*")
+			message.Fail("_Need URL or song name!_\n*Example: .song URL/song name*")
 			return
 		}
 
+		progress := message.Progress("_Searching..._")
+
 		var videoURL string
 		if strings.Contains(match, "youtu") {
 			videoURL = match
@@ -92,32 +95,39 @@ func init() {
 			var searchResults []YTSearchItem
 			err := getJSON(fmt.Sprintf("https://api-25ca.onrender.com/api/yts?q=%s", url.QueryEscape(match)), &searchResults)
 			if err != nil || len(searchResults) == 0 {
-				message.Reply("_No results found_")
+				progress.Fail("_No results found_")
 				return
 			}
 			videoURL = searchResults[0].URL
 		}
 
-		message.Reply("_Downloading audio..._")
+		progress.Update("_Downloading audio..._")
 
 		var audio YTAudioResponse
 		err := getJSON(fmt.Sprintf("https://api-25ca.onrender.com/api/yta?url=%s&format=mp3", url.QueryEscape(videoURL)), &audio)
 		if err != nil || !audio.Status {
-			message.Reply("_Failed to download audio_")
+			progress.Fail("_Failed to download audio_")
 			return
 		}
 
-		data, err := downloadBuffer(audio.Result.Download)
+		data, err := downloadBuffer(audio.Result.Download, progress, "Downloading audio")
 		if err != nil {
-			message.Reply(fmt.Sprintf("_Error downloading audio: %v_", err))
+			progress.Fail(fmt.Sprintf("_Error downloading audio: %v_", err))
 			return
 		}
 
-		message.Send("audio", data, lib.SendOptions{
+		progress.Update("_Uploading..._")
+
+		_, err = message.Send("audio", data, lib.SendOptions{
 			Caption:  audio.Result.Title,
 			Mimetype: "audio/mpeg",
 			Quoted:   true,
 		})
+		if err != nil {
+			progress.Fail(fmt.Sprintf("_Error sending file: %v_", err))
+			return
+		}
+		progress.Done("")
 	})
 
 	lib.Function(map[string]interface{}{
@@ -125,15 +135,18 @@ func init() {
 		"fromMe":  lib.Mode(),
 		"desc":    "Download video from YouTube",
 		"type":    "download",
+		"react":   true,
 	}, func(message *lib.Message, match string) {
 		if match == "" && message.Quoted != nil {
 			match = message.Quoted.Text
 		}
 		if match == "" {
-			message.Reply("_Need URL or video name!_\n*Example: .video URL/video name*")
+			message.Fail("_Need URL or video name!_\n*Example: .video URL/video name*")
 			return
 		}
 
+		progress := message.Progress("_Searching..._")
+
 		var videoURL string
 		if strings.Contains(match, "youtu") {
 			videoURL = match
@@ -141,30 +154,37 @@ func init() {
 			var searchResults []YTSearchItem
 			err := getJSON(fmt.Sprintf("https://api-25ca.onrender.com/api/yts?q=%s", url.QueryEscape(match)), &searchResults)
 			if err != nil || len(searchResults) == 0 {
-				message.Reply("_No results found_")
+				progress.Fail("_No results found_")
 				return
 			}
 			videoURL = searchResults[0].URL
 		}
 
-		message.Reply("_Downloading video..._")
+		progress.Update("_Downloading video..._")
 
 		var video YTVideoResponse
 		err := getJSON(fmt.Sprintf("https://api-25ca.onrender.com/api/ytv?url=%s&format=360", url.QueryEscape(videoURL)), &video)
 		if err != nil || !video.Status {
-			message.Reply("_Failed to download video_")
+			progress.Fail("_Failed to download video_")
 			return
 		}
 
-		data, err := downloadBuffer(video.Result.Download)
+		data, err := downloadBuffer(video.Result.Download, progress, "Downloading video")
 		if err != nil {
-			message.Reply(fmt.Sprintf("_Error downloading video: %v_", err))
+			progress.Fail(fmt.Sprintf("_Error downloading video: %v_", err))
 			return
 		}
 
-		message.Send("video", data, lib.SendOptions{
+		progress.Update("_Uploading..._")
+
+		_, err = message.Send("video", data, lib.SendOptions{
 			Caption: video.Result.Title,
 			Quoted:  true,
 		})
+		if err != nil {
+			progress.Fail(fmt.Sprintf("_Error sending file: %v_", err))
+			return
+		}
+		progress.Done("")
 	})
 }